@@ -1,7 +1,9 @@
 package astilectron
 
 import (
+	"context"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -18,9 +20,10 @@ import (
 
 // Versions
 const (
-	DefaultAcceptTCPTimeout = 30 * time.Second
-	VersionAstilectron      = "0.27.3"
-	VersionElectron         = "1.8.1"
+	DefaultAcceptTCPTimeout   = 30 * time.Second
+	DefaultBeforeCloseTimeout = 10 * time.Second
+	VersionAstilectron        = "0.27.3"
+	VersionElectron           = "1.8.1"
 )
 
 // Misc vars
@@ -34,47 +37,69 @@ var (
 
 // App event names
 const (
-	EventNameAppClose         = "app.close"
-	EventNameAppCmdQuit       = "app.cmd.quit" // Sends an event to Electron to properly quit the app
-	EventNameAppCmdStop       = "app.cmd.stop" // Cancel the context which results in exiting abruptly Electron's app
-	EventNameAppCrash         = "app.crash"
-	EventNameAppErrorAccept   = "app.error.accept"
-	EventNameAppEventReady    = "app.event.ready"
-	EventNameAppNoAccept      = "app.no.accept"
-	EventNameAppTooManyAccept = "app.too.many.accept"
+	EventNameAppClose                  = "app.close"
+	EventNameAppCmdBeforeQuitCallback  = "app.cmd.before.quit.callback"
+	EventNameAppCmdInterceptBeforeQuit = "app.cmd.intercept.before.quit"
+	EventNameAppCmdQuit                = "app.cmd.quit" // Sends an event to Electron to properly quit the app
+	EventNameAppCmdSetBadgeCount       = "app.cmd.set.badge.count"
+	EventNameAppCmdStop                = "app.cmd.stop" // Cancel the context which results in exiting abruptly Electron's app
+	EventNameAppCrash                  = "app.crash"
+	EventNameAppErrorAccept            = "app.error.accept"
+	EventNameAppEventBeforeQuit        = "app.event.before.quit"
+	EventNameAppEventReady             = "app.event.ready"
+	EventNameAppEventSetBadgeCount     = "app.event.set.badge.count"
+	EventNameAppNoAccept               = "app.no.accept"
+	EventNameAppTooManyAccept          = "app.too.many.accept"
 )
 
 // Astilectron represents an object capable of interacting with Astilectron
 type Astilectron struct {
-	canceller    *asticontext.Canceller
-	channelQuit  chan bool
-	closeOnce    sync.Once
-	dispatcher   *dispatcher
-	displayPool  *displayPool
-	dock         *Dock
-	executer     Executer
-	identifier   *identifier
-	listener     net.Listener
-	options      Options
-	paths        *Paths
-	provisioner  Provisioner
-	reader       *reader
-	stderrWriter *astiexec.StdWriter
-	stdoutWriter *astiexec.StdWriter
-	supported    *Supported
-	writer       *writer
+	beforeQuit              func(ctx context.Context) (allow bool)
+	canceller               *asticontext.Canceller
+	certificateError        func(ctx context.Context, e CertificateErrorEvent) (trust bool)
+	channelQuit             chan bool
+	clipboard               *Clipboard
+	clipboardSel            *Clipboard
+	closeOnce               sync.Once
+	credentialProvider      CredentialProvider
+	credentials             map[string]*credentialEntry // Indexed by realm
+	dispatcher              *dispatcher
+	displayPool             *displayPool
+	dock                    *Dock
+	executer                Executer
+	globalShortcuts         map[string]Listener // Indexed by accelerator
+	identifier              *identifier
+	listener                net.Listener
+	m                       sync.Mutex // Locks beforeQuit, certificateError, credentialProvider, credentials, globalShortcuts, protocols, selectClientCertificate, started and writer
+	ms                      sync.Mutex // Locks sessions
+	options                 Options
+	paths                   *Paths
+	powerMonitor            *PowerMonitor
+	protocols               map[string]http.Handler // Indexed by scheme
+	provisioner             Provisioner
+	reader                  *reader
+	selectClientCertificate func(ctx context.Context, e SelectClientCertificateEvent) (c *Certificate)
+	sessions                map[string]*sessionEntry // Indexed by partition
+	started                 bool
+	stderrWriter            *astiexec.StdWriter
+	stdoutWriter            *astiexec.StdWriter
+	supported               *Supported
+	writer                  *writer
 }
 
 // Options represents Astilectron options
 type Options struct {
-	AcceptTCPTimeout   time.Duration
-	AppName            string
-	AppIconDarwinPath  string    // Darwin systems requires a specific .icns file
-	AppIconDefaultPath string
-	BaseDirectoryPath  string
-	DataDirectoryPath  string
-	ElectronSwitches   []string  // eg: []string{"ignore-certificate-errors","true"}
-	SingleInstance     bool
+	AcceptTCPTimeout      time.Duration
+	AppName               string
+	AppIconDarwinPath     string // Darwin systems requires a specific .icns file
+	AppIconDefaultPath    string
+	BaseDirectoryPath     string
+	BeforeCloseTimeout    time.Duration // Maximum duration of before close and before quit handlers, defaults to DefaultBeforeCloseTimeout
+	CredentialMaxAttempts int           // Number of wrong credentials after which an authentication is cancelled, defaults to DefaultCredentialMaxAttempts
+	DataDirectoryPath     string
+	ElectronSwitches      []string // eg: []string{"ignore-certificate-errors","true"}
+	OpenExternalSchemes   []string // URL schemes OpenExternal allows, defaults to DefaultOpenExternalSchemes
+	SingleInstance        bool
 }
 
 // Supported represents Astilectron supported features
@@ -92,14 +117,17 @@ func New(o Options) (a *Astilectron, err error) {
 
 	// Init
 	a = &Astilectron{
-		canceller:   asticontext.NewCanceller(),
-		channelQuit: make(chan bool),
-		dispatcher:  newDispatcher(),
-		displayPool: newDisplayPool(),
-		executer:    DefaultExecuter,
-		identifier:  newIdentifier(),
-		options:     o,
-		provisioner: DefaultProvisioner,
+		canceller:       asticontext.NewCanceller(),
+		channelQuit:     make(chan bool),
+		dispatcher:      newDispatcher(),
+		displayPool:     newDisplayPool(),
+		executer:        DefaultExecuter,
+		globalShortcuts: make(map[string]Listener),
+		identifier:      newIdentifier(),
+		options:         o,
+		protocols:       make(map[string]http.Handler),
+		provisioner:     DefaultProvisioner,
+		sessions:        make(map[string]*sessionEntry),
 	}
 
 	// Set paths
@@ -113,6 +141,25 @@ func New(o Options) (a *Astilectron, err error) {
 		a.Stop()
 		return
 	})
+	a.On(EventNameAppEventBeforeQuit, func(e Event) (deleteListener bool) {
+		a.m.Lock()
+		var fn = a.beforeQuit
+		a.m.Unlock()
+		var allow = true
+		if fn != nil {
+			allow = closeDecision(a.beforeCloseTimeout(), fn)
+		}
+		if err := a.writer.write(Event{Allow: PtrBool(allow), CallbackID: e.CallbackID, Name: EventNameAppCmdBeforeQuitCallback, TargetID: targetIDApp}); err != nil {
+			astilog.Error(errors.Wrap(err, "writing before quit callback message failed"))
+		}
+		return
+	})
+	a.On(EventNameAppEventCertificateError, a.onCertificateError)
+	a.On(EventNameAppEventSelectClientCertificate, a.onSelectClientCertificate)
+	a.On(EventNameAppEventLogin, a.onLogin)
+	a.On(EventNameAppEventLoginSucceeded, a.onLoginSucceeded)
+	a.On(EventNameGlobalShortcutEventTriggered, a.onGlobalShortcutTriggered)
+	a.On(EventNameProtocolEventRequest, a.onProtocolRequest)
 	a.On(EventNameDisplayEventAdded, func(e Event) (deleteListener bool) {
 		a.displayPool.update(e.Displays)
 		return
@@ -159,6 +206,11 @@ func (a *Astilectron) Start() (err error) {
 	// Log
 	astilog.Debug("Starting...")
 
+	// Protocols are sent to Electron when it's executed, so that they can't be registered anymore from now on
+	a.m.Lock()
+	a.started = true
+	a.m.Unlock()
+
 	// Provision
 	if err = a.provision(); err != nil {
 		return errors.Wrap(err, "provisioning failed")
@@ -241,7 +293,9 @@ func (a *Astilectron) acceptTCP(chanAccepted chan bool) {
 	chanAccepted <- true
 
 	// Create reader and writer
+	a.m.Lock()
 	a.writer = newWriter(conn)
+	a.m.Unlock()
 	ctx, _ := a.canceller.NewContext()
 	a.reader = newReader(ctx, a.dispatcher, conn)
 	go a.reader.read()
@@ -269,6 +323,9 @@ func (a *Astilectron) execute() (err error) {
 	a.stdoutWriter = astiexec.NewStdWriter(func(i []byte) { astilog.Debugf("Stdout says: %s", i) })
 	cmd.Stderr = a.stderrWriter
 	cmd.Stdout = a.stdoutWriter
+	if schemes := a.privilegedSchemes(); len(schemes) > 0 {
+		cmd.Env = append(os.Environ(), privilegedSchemesEnv(schemes))
+	}
 
 	// Execute command
 	if err = a.executeCmd(cmd); err != nil {
@@ -291,8 +348,44 @@ func (a *Astilectron) executeCmd(cmd *exec.Cmd) (err error) {
 	// Create dock
 	a.dock = newDock(a.canceller, a.dispatcher, a.identifier, a.writer)
 
+	// Create power monitor
+	a.powerMonitor = newPowerMonitor(a.canceller, a.dispatcher, a.identifier, a.writer)
+
+	// Create clipboards
+	a.clipboard = newClipboard(a.canceller, a.dispatcher, a.identifier, a.writer, targetIDClipboard)
+	a.clipboardSel = newClipboard(a.canceller, a.dispatcher, a.identifier, a.writer, targetIDClipboardSelection)
+
 	// Update supported features
 	a.supported = e.Supported
+
+	// Register protocols
+	if err = a.registerProtocols(); err != nil {
+		err = errors.Wrap(err, "registering protocols failed")
+		return
+	}
+
+	// Intercept certificates
+	if err = a.interceptCertificates(); err != nil {
+		err = errors.Wrap(err, "intercepting certificates failed")
+		return
+	}
+
+	// Intercept login
+	if err = a.interceptLogin(); err != nil {
+		err = errors.Wrap(err, "intercepting login failed")
+		return
+	}
+
+	// Intercept before quit
+	a.m.Lock()
+	var intercept = a.beforeQuit != nil
+	a.m.Unlock()
+	if intercept {
+		if err = a.interceptBeforeQuit(true); err != nil {
+			err = errors.Wrap(err, "intercepting before quit failed")
+			return
+		}
+	}
 	return
 }
 
@@ -315,6 +408,14 @@ func (a *Astilectron) watchCmd(cmd *exec.Cmd) {
 // Close closes Astilectron properly
 func (a *Astilectron) Close() {
 	astilog.Debug("Closing...")
+	a.m.Lock()
+	var hasGlobalShortcuts = len(a.globalShortcuts) > 0
+	a.m.Unlock()
+	if hasGlobalShortcuts && a.writer != nil && !a.canceller.Cancelled() {
+		if err := a.writer.write(Event{Name: EventNameGlobalShortcutCmdUnregisterAll, TargetID: targetIDApp}); err != nil {
+			astilog.Error(errors.Wrap(err, "unregistering global shortcuts failed"))
+		}
+	}
 	a.canceller.Cancel()
 	if a.listener != nil {
 		a.listener.Close()
@@ -364,6 +465,44 @@ func (a *Astilectron) Quit() error {
 	return a.writer.write(Event{Name: EventNameAppCmdQuit})
 }
 
+// SetBadgeCount sets the counter badge of the app, 0 hiding the badge
+// It works on macOS and on Linux with the Unity launcher, an error is returned otherwise
+func (a *Astilectron) SetBadgeCount(ctx context.Context, n int) (err error) {
+	_, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{BadgeCount: PtrInt(n), CallbackID: a.identifier.new(), Name: EventNameAppCmdSetBadgeCount, TargetID: targetIDApp}, EventNameAppEventSetBadgeCount)
+	return
+}
+
+// OnBeforeQuit sets the handler deciding whether the app is allowed to quit, a nil handler removing it
+// Electron waits for the decision which is made within Options.BeforeCloseTimeout, the app quitting otherwise
+func (a *Astilectron) OnBeforeQuit(fn func(ctx context.Context) (allow bool)) (err error) {
+	a.m.Lock()
+	a.beforeQuit = fn
+	var started = a.writer != nil
+	a.m.Unlock()
+	if started {
+		err = a.interceptBeforeQuit(fn != nil)
+	}
+	return
+}
+
+// beforeCloseTimeout returns the maximum duration of before close and before quit handlers
+func (a *Astilectron) beforeCloseTimeout() time.Duration {
+	if a.options.BeforeCloseTimeout > 0 {
+		return a.options.BeforeCloseTimeout
+	}
+	return DefaultBeforeCloseTimeout
+}
+
+// interceptBeforeQuit asks Electron to wait for the GO decision before quitting or, if intercept is false, to stop
+// waiting for it
+func (a *Astilectron) interceptBeforeQuit(intercept bool) error {
+	var e = Event{Name: EventNameAppCmdInterceptBeforeQuit, TargetID: targetIDApp}
+	if intercept {
+		e.Timeout = PtrInt(int(a.beforeCloseTimeout() / time.Millisecond))
+	}
+	return a.writer.write(e)
+}
+
 // Paths returns the paths
 func (a *Astilectron) Paths() Paths {
 	return *a.paths
@@ -374,6 +513,22 @@ func (a *Astilectron) Displays() []*Display {
 	return a.displayPool.all()
 }
 
+// Clipboard returns the clipboard
+func (a *Astilectron) Clipboard() *Clipboard {
+	return a.clipboard
+}
+
+// PowerMonitor returns the power monitor
+func (a *Astilectron) PowerMonitor() *PowerMonitor {
+	return a.powerMonitor
+}
+
+// SelectionClipboard returns the selection clipboard
+// It only works on Linux
+func (a *Astilectron) SelectionClipboard() *Clipboard {
+	return a.clipboardSel
+}
+
 // Dock returns the dock
 func (a *Astilectron) Dock() *Dock {
 	return a.dock
@@ -394,6 +549,16 @@ func (a *Astilectron) NewWindow(url string, o *WindowOptions) (*Window, error) {
 	return newWindow(a.options, a.Paths(), url, o, a.canceller, a.dispatcher, a.identifier, a.writer)
 }
 
+// NewChildWindow creates a new window whose parent is the provided window
+// Set the Modal option to true to disable the parent window while the child window is shown, see Window.ShowModal
+func (a *Astilectron) NewChildWindow(parent *Window, url string, o *WindowOptions) (w *Window, err error) {
+	if w, err = a.NewWindow(url, o); err != nil {
+		return
+	}
+	w.setParent(parent)
+	return
+}
+
 // NewWindowInDisplay creates a new window in a specific display
 // This overrides the center attribute
 func (a *Astilectron) NewWindowInDisplay(d *Display, url string, o *WindowOptions) (*Window, error) {
@@ -410,6 +575,49 @@ func (a *Astilectron) NewWindowInDisplay(d *Display, url string, o *WindowOption
 	return newWindow(a.options, a.Paths(), url, o, a.canceller, a.dispatcher, a.identifier, a.writer)
 }
 
+// PrintToPDF loads the url in a window, prints it as PDF and destroys the window
+// Unless specified otherwise in wo, the window is hidden, skips the taskbar and renders offscreen
+func (a *Astilectron) PrintToPDF(ctx context.Context, url string, wo *WindowOptions, o PrintToPDFOptions) (b []byte, err error) {
+	// Default window options without overriding the caller's
+	var wco = WindowOptions{}
+	if wo != nil {
+		wco = *wo
+	}
+	if wco.Show == nil {
+		wco.Show = PtrBool(false)
+	}
+	if wco.SkipTaskbar == nil {
+		wco.SkipTaskbar = PtrBool(true)
+	}
+	var wp = WebPreferences{}
+	if wco.WebPreferences != nil {
+		wp = *wco.WebPreferences
+	}
+	if wp.Offscreen == nil {
+		wp.Offscreen = PtrBool(true)
+	}
+	wco.WebPreferences = &wp
+
+	// Create window
+	var w *Window
+	if w, err = a.NewWindow(url, &wco); err != nil {
+		err = errors.Wrap(err, "creating window failed")
+		return
+	}
+	if err = w.Create(); err != nil {
+		err = errors.Wrap(err, "creating window failed")
+		return
+	}
+	defer w.Destroy()
+
+	// Print
+	if b, err = w.PrintToPDF(ctx, o); err != nil {
+		err = errors.Wrapf(err, "printing %s to PDF failed", url)
+		return
+	}
+	return
+}
+
 // NewTray creates a new tray
 func (a *Astilectron) NewTray(o *TrayOptions) *Tray {
 	return newTray(o, a.canceller, a.dispatcher, a.identifier, a.writer)
@@ -1,6 +1,7 @@
 package astilectron
 
 import (
+	"context"
 	"net"
 	"os"
 	"sync"
@@ -182,3 +183,72 @@ func TestAstilectron_Actions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"{\"name\":\"app.cmd.quit\"}\n"}, wrt.w)
 }
+
+func TestAstilectron_SetBadgeCount(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameAppEventSetBadgeCount, TargetID: targetIDApp})
+	}
+	err = a.SetBadgeCount(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdSetBadgeCount + "\",\"targetID\":\"app\",\"badgeCount\":3,\"callbackId\":\"1\"}\n"}, wrt.w)
+}
+
+func TestAstilectron_PrintToPDF(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	wrt.fn = func() {
+		switch len(wrt.w) {
+		case 1:
+			a.dispatcher.dispatch(Event{Name: EventNameWindowEventDidFinishLoad, TargetID: "1"})
+		case 2:
+			a.dispatcher.dispatch(Event{CallbackID: "1", Data: []byte("pdf"), Index: PtrInt(0), Name: EventNameWindowEventWebContentsPrintedToPDF, TargetID: "1"})
+			a.dispatcher.dispatch(Event{CallbackID: "1", Count: PtrInt(1), Name: EventNameWindowEventWebContentsPrintedToPDF, TargetID: "1"})
+		case 3:
+			a.dispatcher.dispatch(Event{Name: EventNameWindowEventClosed, TargetID: "1"})
+		}
+	}
+	b, err := a.PrintToPDF(context.Background(), "http://test.com", &WindowOptions{Show: PtrBool(true), WebPreferences: &WebPreferences{Offscreen: PtrBool(false)}}, PrintToPDFOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pdf"), b)
+	assert.Len(t, wrt.w, 3)
+	assert.Contains(t, wrt.w[0], "\"show\":true")
+	assert.Contains(t, wrt.w[0], "\"skipTaskbar\":true")
+	assert.Contains(t, wrt.w[0], "\"offscreen\":false")
+}
+
+func TestAstilectron_OnBeforeQuit(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{wg: &sync.WaitGroup{}}
+	a.writer = newWriter(wrt)
+
+	// Intercept
+	wrt.wg.Add(1)
+	err = a.OnBeforeQuit(func(ctx context.Context) bool { return false })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdInterceptBeforeQuit + "\",\"targetID\":\"app\",\"timeout\":10000}\n"}, wrt.w)
+
+	// Veto
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameAppEventBeforeQuit, TargetID: targetIDApp})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdBeforeQuitCallback + "\",\"targetID\":\"app\",\"allow\":false,\"callbackId\":\"1\"}\n"}, wrt.w)
+
+	// A nil handler stops intercepting
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	err = a.OnBeforeQuit(nil)
+	assert.NoError(t, err)
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdInterceptBeforeQuit + "\",\"targetID\":\"app\"}\n"}, wrt.w)
+}
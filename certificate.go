@@ -0,0 +1,219 @@
+package astilectron
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/asticode/go-astilog"
+	"github.com/pkg/errors"
+)
+
+// Certificate event names
+const (
+	EventNameAppCmdCertificateErrorCallback         = "app.cmd.certificate.error.callback"
+	EventNameAppCmdInterceptCertificateError        = "app.cmd.intercept.certificate.error"
+	EventNameAppCmdInterceptSelectClientCertificate = "app.cmd.intercept.select.client.certificate"
+	EventNameAppCmdSelectClientCertificateCallback  = "app.cmd.select.client.certificate.callback"
+	EventNameAppEventCertificateError               = "app.event.certificate.error"
+	EventNameAppEventSelectClientCertificate        = "app.event.select.client.certificate"
+	EventNameSessionCmdCertificateVerifyCallback    = "session.cmd.certificate.verify.callback"
+	EventNameSessionCmdSetCertificateVerifyProc     = "session.cmd.set.certificate.verify.proc"
+	EventNameSessionEventCertificateVerify          = "session.event.certificate.verify"
+	EventNameSessionEventSetCertificateVerifyProc   = "session.event.set.certificate.verify.proc"
+)
+
+// Certificate verify results
+const (
+	CertificateVerifyResultAccept      = 0
+	CertificateVerifyResultReject      = -2
+	CertificateVerifyResultUseChromium = -3
+)
+
+// DefaultCertificateHandlerTimeout represents the maximum duration of certificate handlers, the certificate is not
+// trusted and no client certificate is selected once it is reached
+const DefaultCertificateHandlerTimeout = 10 * time.Second
+
+// Certificate represents a certificate
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/structures/certificate.md
+type Certificate struct {
+	Data         string                `json:"data,omitempty"` // PEM encoded data
+	Fingerprint  string                `json:"fingerprint,omitempty"`
+	Issuer       *CertificatePrincipal `json:"issuer,omitempty"`
+	IssuerCert   *Certificate          `json:"issuerCert,omitempty"`
+	IssuerName   string                `json:"issuerName,omitempty"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Subject      *CertificatePrincipal `json:"subject,omitempty"`
+	SubjectName  string                `json:"subjectName,omitempty"`
+	ValidExpiry  int64                 `json:"validExpiry,omitempty"` // In seconds since the UNIX epoch
+	ValidStart   int64                 `json:"validStart,omitempty"`  // In seconds since the UNIX epoch
+}
+
+// CertificatePrincipal represents a certificate principal
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/structures/certificate-principal.md
+type CertificatePrincipal struct {
+	CommonName        string   `json:"commonName,omitempty"`
+	Country           string   `json:"country,omitempty"`
+	Locality          string   `json:"locality,omitempty"`
+	Organizations     []string `json:"organizations,omitempty"`
+	OrganizationUnits []string `json:"organizationUnits,omitempty"`
+	State             string   `json:"state,omitempty"`
+}
+
+// X509 parses the PEM encoded data of the certificate
+func (c Certificate) X509() (x *x509.Certificate, err error) {
+	var b, _ = pem.Decode([]byte(c.Data))
+	if b == nil {
+		err = errors.New("no PEM data found")
+		return
+	}
+	if x, err = x509.ParseCertificate(b.Bytes); err != nil {
+		err = errors.Wrap(err, "parsing certificate failed")
+		return
+	}
+	return
+}
+
+// CertificateErrorEvent represents a certificate error
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/app.md#event-certificate-error
+type CertificateErrorEvent struct {
+	Certificate *Certificate `json:"certificate,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	WindowID    string       `json:"windowId,omitempty"`
+}
+
+// CertificateVerifyRequest represents a certificate verify request
+// VerificationResult and ErrorCode hold the result of Chromium's own verification
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/session.md#sessetcertificateverifyprocproc
+type CertificateVerifyRequest struct {
+	Certificate        *Certificate `json:"certificate,omitempty"`
+	ErrorCode          int          `json:"errorCode,omitempty"`
+	Hostname           string       `json:"hostname,omitempty"`
+	VerificationResult string       `json:"verificationResult,omitempty"`
+}
+
+// SelectClientCertificateEvent represents a client certificate request
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/app.md#event-select-client-certificate
+type SelectClientCertificateEvent struct {
+	Certificates []Certificate `json:"certificates,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	WindowID     string        `json:"windowId,omitempty"`
+}
+
+// onCertificateError sends the OnCertificateError handler decision back to the JS
+// The certificate is not trusted if there's no handler or if the handler doesn't return before the context is done
+func (a *Astilectron) onCertificateError(e Event) (deleteListener bool) {
+	a.m.Lock()
+	var fn = a.certificateError
+	a.m.Unlock()
+	var trust bool
+	if fn != nil && e.CertificateError != nil {
+		var ce = *e.CertificateError
+		trust = timedDecision(DefaultCertificateHandlerTimeout, false, func(ctx context.Context) bool { return fn(ctx, ce) })
+	}
+	if err := a.writer.write(Event{Allow: PtrBool(trust), CallbackID: e.CallbackID, Name: EventNameAppCmdCertificateErrorCallback, TargetID: targetIDApp}); err != nil {
+		astilog.Error(errors.Wrap(err, "writing certificate error callback message failed"))
+	}
+	return
+}
+
+// onSelectClientCertificate sends the certificate selected by the OnSelectClientCertificate handler back to the JS
+// No certificate is selected if there's no handler or if the handler doesn't return before the context is done
+func (a *Astilectron) onSelectClientCertificate(e Event) (deleteListener bool) {
+	a.m.Lock()
+	var fn = a.selectClientCertificate
+	a.m.Unlock()
+	var c *Certificate
+	if fn != nil && e.CertificateSelect != nil {
+		var cs = *e.CertificateSelect
+		c = timedValue(DefaultCertificateHandlerTimeout, c, func(ctx context.Context) interface{} { return fn(ctx, cs) }).(*Certificate)
+	}
+	if err := a.writer.write(Event{CallbackID: e.CallbackID, Certificate: c, Name: EventNameAppCmdSelectClientCertificateCallback, TargetID: targetIDApp}); err != nil {
+		astilog.Error(errors.Wrap(err, "writing select client certificate callback message failed"))
+	}
+	return
+}
+
+// interceptCertificates asks Electron to wait for the GO decision on certificate events having a handler
+func (a *Astilectron) interceptCertificates() (err error) {
+	a.m.Lock()
+	var ce, scc = a.certificateError != nil, a.selectClientCertificate != nil
+	a.m.Unlock()
+	if ce {
+		if err = a.interceptCertificateEvent(EventNameAppCmdInterceptCertificateError); err != nil {
+			return
+		}
+	}
+	if scc {
+		err = a.interceptCertificateEvent(EventNameAppCmdInterceptSelectClientCertificate)
+	}
+	return
+}
+
+// interceptCertificateEvent asks Electron to wait for the GO decision on a certificate event
+func (a *Astilectron) interceptCertificateEvent(eventNameCmd string) error {
+	return a.writer.write(Event{Name: eventNameCmd, TargetID: targetIDApp, Timeout: PtrInt(int(DefaultCertificateHandlerTimeout / time.Millisecond))})
+}
+
+// OnCertificateError sets the handler deciding whether a certificate failing Chromium's verification is trusted
+// anyway, which is safer than disabling all checks with the ignore-certificate-errors switch
+// The certificate is not trusted if the handler doesn't return within DefaultCertificateHandlerTimeout
+func (a *Astilectron) OnCertificateError(fn func(ctx context.Context, e CertificateErrorEvent) (trust bool)) (err error) {
+	a.m.Lock()
+	a.certificateError = fn
+	var started = a.writer != nil
+	a.m.Unlock()
+	if started {
+		err = a.interceptCertificateEvent(EventNameAppCmdInterceptCertificateError)
+	}
+	return
+}
+
+// OnSelectClientCertificate sets the handler selecting the certificate used to authenticate to a server requesting
+// a client certificate
+// No certificate is selected if the handler returns nil or doesn't return within DefaultCertificateHandlerTimeout
+func (a *Astilectron) OnSelectClientCertificate(fn func(ctx context.Context, e SelectClientCertificateEvent) (c *Certificate)) (err error) {
+	a.m.Lock()
+	a.selectClientCertificate = fn
+	var started = a.writer != nil
+	a.m.Unlock()
+	if started {
+		err = a.interceptCertificateEvent(EventNameAppCmdInterceptSelectClientCertificate)
+	}
+	return
+}
+
+// onCertificateVerify sends the certificate verify proc result back to the JS
+// Chromium's result is used if there's no verify proc or if the proc doesn't return before the context is done
+func (s *Session) onCertificateVerify(e Event) (deleteListener bool) {
+	s.m.Lock()
+	var fn = s.certificateVerifyProc
+	s.m.Unlock()
+	var r = CertificateVerifyResultUseChromium
+	if fn != nil && e.CertificateVerify != nil {
+		var cv = *e.CertificateVerify
+		r = timedValue(DefaultCertificateHandlerTimeout, r, func(ctx context.Context) interface{} { return fn(ctx, cv) }).(int)
+	}
+	if err := s.w.write(Event{CallbackID: e.CallbackID, Name: EventNameSessionCmdCertificateVerifyCallback, Response: PtrInt(r), TargetID: s.id}); err != nil {
+		astilog.Error(errors.Wrap(err, "writing certificate verify callback message failed"))
+	}
+	return
+}
+
+// SetCertificateVerifyProc sets the proc verifying the server certificates of the session, e.g. to pin a CA
+// The proc returns one of the CertificateVerifyResult constants, Chromium's result is used if it doesn't return within
+// DefaultCertificateHandlerTimeout
+// A nil proc restores Electron's default verification
+func (s *Session) SetCertificateVerifyProc(fn func(ctx context.Context, r CertificateVerifyRequest) (result int)) (err error) {
+	s.m.Lock()
+	s.certificateVerifyProc = fn
+	s.m.Unlock()
+	var i = Event{Name: EventNameSessionCmdSetCertificateVerifyProc}
+	if fn != nil {
+		i.Timeout = PtrInt(int(DefaultCertificateHandlerTimeout / time.Millisecond))
+	}
+	_, err = s.sendEvent(i, EventNameSessionEventSetCertificateVerifyProc)
+	return
+}
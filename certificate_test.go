@@ -0,0 +1,125 @@
+package astilectron
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificate_X509(t *testing.T) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tpl := &x509.Certificate{NotAfter: time.Now().Add(time.Hour), NotBefore: time.Now(), SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test"}}
+	b, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &k.PublicKey, k)
+	assert.NoError(t, err)
+	x, err := Certificate{Data: string(pem.EncodeToMemory(&pem.Block{Bytes: b, Type: "CERTIFICATE"}))}.X509()
+	assert.NoError(t, err)
+	assert.Equal(t, "test", x.Subject.CommonName)
+	_, err = Certificate{Data: "invalid"}.X509()
+	assert.Error(t, err)
+}
+
+func TestAstilectron_Certificates(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{wg: &sync.WaitGroup{}}
+	a.writer = newWriter(wrt)
+
+	// Certificate error
+	wrt.wg.Add(1)
+	err = a.OnCertificateError(func(ctx context.Context, e CertificateErrorEvent) bool {
+		return e.Certificate != nil && e.Certificate.Fingerprint == "sha256/fingerprint"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdInterceptCertificateError + "\",\"targetID\":\"app\",\"timeout\":10000}\n"}, wrt.w)
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{CallbackID: "10", CertificateError: &CertificateErrorEvent{Certificate: &Certificate{Fingerprint: "sha256/fingerprint"}, Error: "net::ERR_CERT_AUTHORITY_INVALID", URL: "https://localhost"}, Name: EventNameAppEventCertificateError, TargetID: targetIDApp})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdCertificateErrorCallback + "\",\"targetID\":\"app\",\"allow\":true,\"callbackId\":\"10\"}\n"}, wrt.w)
+
+	// Select client certificate
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	err = a.OnSelectClientCertificate(func(ctx context.Context, e SelectClientCertificateEvent) *Certificate { return &e.Certificates[1] })
+	assert.NoError(t, err)
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{CallbackID: "11", CertificateSelect: &SelectClientCertificateEvent{Certificates: []Certificate{{Fingerprint: "1"}, {Fingerprint: "2"}}}, Name: EventNameAppEventSelectClientCertificate, TargetID: targetIDApp})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{
+		"{\"name\":\"" + EventNameAppCmdInterceptSelectClientCertificate + "\",\"targetID\":\"app\",\"timeout\":10000}\n",
+		"{\"name\":\"" + EventNameAppCmdSelectClientCertificateCallback + "\",\"targetID\":\"app\",\"callbackId\":\"11\",\"certificate\":{\"fingerprint\":\"2\"}}\n",
+	}, wrt.w)
+
+	// Intercept on start
+	wrt.w = []string{}
+	wrt.wg.Add(2)
+	assert.NoError(t, a.interceptCertificates())
+	assert.Len(t, wrt.w, 2)
+}
+
+func TestSession_SetCertificateVerifyProc(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+
+	// No proc
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "10", CertificateVerify: &CertificateVerifyRequest{Hostname: "github.com"}, Name: EventNameSessionEventCertificateVerify, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdCertificateVerifyCallback + "\",\"targetID\":\"1\",\"callbackId\":\"10\",\"response\":-3}\n"}, wrt.w)
+
+	// Set proc
+	wrt.wg = nil
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventSetCertificateVerifyProc, TargetID: s.id})
+	}
+	err := s.SetCertificateVerifyProc(func(ctx context.Context, r CertificateVerifyRequest) int {
+		if r.Certificate != nil && r.Certificate.IssuerName == "My CA" {
+			return CertificateVerifyResultAccept
+		}
+		return CertificateVerifyResultReject
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetCertificateVerifyProc + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\",\"timeout\":10000}\n"}, wrt.w)
+
+	// Verify
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "11", CertificateVerify: &CertificateVerifyRequest{Certificate: &Certificate{IssuerName: "Other CA"}, Hostname: "github.com"}, Name: EventNameSessionEventCertificateVerify, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdCertificateVerifyCallback + "\",\"targetID\":\"1\",\"callbackId\":\"11\",\"response\":-2}\n"}, wrt.w)
+
+	// A nil proc restores Electron's default verification
+	wrt.wg = nil
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "3", Name: EventNameSessionEventSetCertificateVerifyProc, TargetID: s.id})
+	}
+	err = s.SetCertificateVerifyProc(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetCertificateVerifyProc + "\",\"targetID\":\"1\",\"callbackId\":\"3\",\"partition\":\"\"}\n"}, wrt.w)
+	s.m.Lock()
+	assert.Nil(t, s.certificateVerifyProc)
+	s.m.Unlock()
+}
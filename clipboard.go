@@ -0,0 +1,233 @@
+package astilectron
+
+import (
+	"context"
+	"time"
+
+	"github.com/asticode/go-astilog"
+	"github.com/asticode/go-astitools/context"
+	"github.com/pkg/errors"
+)
+
+// Clipboard event names
+const (
+	EventNameClipboardCmdAvailableFormats   = "clipboard.cmd.available.formats"
+	EventNameClipboardCmdClear              = "clipboard.cmd.clear"
+	EventNameClipboardCmdReadBookmark       = "clipboard.cmd.read.bookmark"
+	EventNameClipboardCmdReadHTML           = "clipboard.cmd.read.html"
+	EventNameClipboardCmdReadImage          = "clipboard.cmd.read.image"
+	EventNameClipboardCmdReadRTF            = "clipboard.cmd.read.rtf"
+	EventNameClipboardCmdReadText           = "clipboard.cmd.read.text"
+	EventNameClipboardCmdWriteBookmark      = "clipboard.cmd.write.bookmark"
+	EventNameClipboardCmdWriteHTML          = "clipboard.cmd.write.html"
+	EventNameClipboardCmdWriteImage         = "clipboard.cmd.write.image"
+	EventNameClipboardCmdWriteRTF           = "clipboard.cmd.write.rtf"
+	EventNameClipboardCmdWriteText          = "clipboard.cmd.write.text"
+	EventNameClipboardEventAvailableFormats = "clipboard.event.available.formats"
+	EventNameClipboardEventChanged          = "clipboard.event.changed"
+	EventNameClipboardEventCleared          = "clipboard.event.cleared"
+	EventNameClipboardEventReadBookmark     = "clipboard.event.read.bookmark"
+	EventNameClipboardEventReadHTML         = "clipboard.event.read.html"
+	EventNameClipboardEventReadImage        = "clipboard.event.read.image"
+	EventNameClipboardEventReadRTF          = "clipboard.event.read.rtf"
+	EventNameClipboardEventReadText         = "clipboard.event.read.text"
+	EventNameClipboardEventWrittenBookmark  = "clipboard.event.written.bookmark"
+	EventNameClipboardEventWrittenHTML      = "clipboard.event.written.html"
+	EventNameClipboardEventWrittenImage     = "clipboard.event.written.image"
+	EventNameClipboardEventWrittenRTF       = "clipboard.event.written.rtf"
+	EventNameClipboardEventWrittenText      = "clipboard.event.written.text"
+)
+
+// Default interval at which the clipboard is polled when watching it
+const DefaultClipboardWatchInterval = 500 * time.Millisecond
+
+// Clipboard represents a clipboard
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/clipboard.md
+type Clipboard struct {
+	*object
+}
+
+func newClipboard(c *asticontext.Canceller, d *dispatcher, i *identifier, wrt *writer, id string) *Clipboard {
+	return &Clipboard{object: newObject(nil, c, d, i, wrt, id)}
+}
+
+// sendEvent sends a clipboard event and waits for its done event
+func (c *Clipboard) sendEvent(i Event, eventNameDone string) (o Event, err error) {
+	if err = c.isActionable(); err != nil {
+		return
+	}
+	i.CallbackID = c.i.new()
+	i.TargetID = c.id
+	o, err = synchronousEventWithContext(c.ctx, c.c, c, c.w, i, eventNameDone)
+	return
+}
+
+// AvailableFormats returns the formats supported by the clipboard content
+func (c *Clipboard) AvailableFormats() (formats []string, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{Name: EventNameClipboardCmdAvailableFormats}, EventNameClipboardEventAvailableFormats); err != nil {
+		return
+	}
+	formats = e.Formats
+	return
+}
+
+// Clear clears the clipboard content
+func (c *Clipboard) Clear() (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameClipboardCmdClear}, EventNameClipboardEventCleared)
+	return
+}
+
+// ReadBookmark reads the bookmark title and url in the clipboard
+// It only works on macOS and Windows
+func (c *Clipboard) ReadBookmark() (title, url string, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{Name: EventNameClipboardCmdReadBookmark}, EventNameClipboardEventReadBookmark); err != nil {
+		return
+	}
+	title, url = e.Title, e.URL
+	return
+}
+
+// ReadHTML reads the content in the clipboard as markup
+func (c *Clipboard) ReadHTML() (html string, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{Name: EventNameClipboardCmdReadHTML}, EventNameClipboardEventReadHTML); err != nil {
+		return
+	}
+	html = e.Text
+	return
+}
+
+// ReadImage reads the image in the clipboard as PNG bytes
+func (c *Clipboard) ReadImage() (png []byte, err error) {
+	if err = c.isActionable(); err != nil {
+		return
+	}
+	png, err = synchronousChunkedEvent(c.ctx, c.c, c, c.w, Event{CallbackID: c.i.new(), Name: EventNameClipboardCmdReadImage, TargetID: c.id}, EventNameClipboardEventReadImage)
+	return
+}
+
+// ReadRTF reads the content in the clipboard as RTF
+func (c *Clipboard) ReadRTF() (rtf string, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{Name: EventNameClipboardCmdReadRTF}, EventNameClipboardEventReadRTF); err != nil {
+		return
+	}
+	rtf = e.Text
+	return
+}
+
+// ReadText reads the content in the clipboard as plain text
+func (c *Clipboard) ReadText() (text string, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{Name: EventNameClipboardCmdReadText}, EventNameClipboardEventReadText); err != nil {
+		return
+	}
+	text = e.Text
+	return
+}
+
+// WriteBookmark writes the title and url into the clipboard as a bookmark
+// It only works on macOS and Windows
+func (c *Clipboard) WriteBookmark(title, url string) (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameClipboardCmdWriteBookmark, Title: title, URL: url}, EventNameClipboardEventWrittenBookmark)
+	return
+}
+
+// WriteHTML writes markup into the clipboard
+func (c *Clipboard) WriteHTML(html string) (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameClipboardCmdWriteHTML, Text: html}, EventNameClipboardEventWrittenHTML)
+	return
+}
+
+// WriteImage writes PNG bytes into the clipboard
+func (c *Clipboard) WriteImage(png []byte) (err error) {
+	_, err = c.sendEvent(Event{Data: png, Name: EventNameClipboardCmdWriteImage}, EventNameClipboardEventWrittenImage)
+	return
+}
+
+// WriteRTF writes RTF into the clipboard
+func (c *Clipboard) WriteRTF(rtf string) (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameClipboardCmdWriteRTF, Text: rtf}, EventNameClipboardEventWrittenRTF)
+	return
+}
+
+// WriteText writes plain text into the clipboard
+func (c *Clipboard) WriteText(text string) (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameClipboardCmdWriteText, Text: text}, EventNameClipboardEventWrittenText)
+	return
+}
+
+// clipboardSnapshot represents the clipboard content compared by the watcher
+type clipboardSnapshot struct {
+	formats []string
+	html    string
+	text    string
+}
+
+func (s clipboardSnapshot) equal(o clipboardSnapshot) bool {
+	if len(s.formats) != len(o.formats) {
+		return false
+	}
+	for idx := range s.formats {
+		if s.formats[idx] != o.formats[idx] {
+			return false
+		}
+	}
+	return s.html == o.html && s.text == o.text
+}
+
+// snapshot reads the clipboard content compared by the watcher
+// The image is not read since it may be large
+func (c *Clipboard) snapshot() (s clipboardSnapshot, err error) {
+	if s.formats, err = c.AvailableFormats(); err != nil {
+		err = errors.Wrap(err, "getting available formats failed")
+		return
+	}
+	if s.text, err = c.ReadText(); err != nil {
+		err = errors.Wrap(err, "reading text failed")
+		return
+	}
+	if s.html, err = c.ReadHTML(); err != nil {
+		err = errors.Wrap(err, "reading html failed")
+		return
+	}
+	return
+}
+
+// Watch polls the clipboard every interval and dispatches an EventNameClipboardEventChanged event holding the
+// available formats and the plain text whenever its content changes
+// Electron doesn't notify clipboard changes, hence the polling. Only the formats, the plain text and the HTML are
+// compared which means replacing an image by another one is not detected.
+// If the interval is not strictly positive, DefaultClipboardWatchInterval is used. Use stop to stop watching.
+func (c *Clipboard) Watch(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultClipboardWatchInterval
+	}
+	var ctx, cancel = context.WithCancel(c.ctx)
+	go func() {
+		var t = time.NewTicker(interval)
+		defer t.Stop()
+		var previous *clipboardSnapshot
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				s, err := c.snapshot()
+				if err != nil {
+					if ctx.Err() == nil {
+						astilog.Error(errors.Wrap(err, "taking clipboard snapshot failed"))
+					}
+					continue
+				}
+				if previous != nil && !previous.equal(s) && ctx.Err() == nil {
+					c.d.dispatch(Event{Formats: s.formats, Name: EventNameClipboardEventChanged, TargetID: c.id, Text: s.text})
+				}
+				previous = &s
+			}
+		}
+	}()
+	return cancel
+}
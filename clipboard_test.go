@@ -0,0 +1,118 @@
+package astilectron
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipboard_Actions(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var cb = newClipboard(c, d, i, w, targetIDClipboard)
+
+	// Read
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "1", Name: EventNameClipboardEventReadText, TargetID: targetIDClipboard, Text: "text"})
+	}
+	s, err := cb.ReadText()
+	assert.NoError(t, err)
+	assert.Equal(t, "text", s)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameClipboardCmdReadText + "\",\"targetID\":\"clipboard\",\"callbackId\":\"1\"}\n"}, wrt.w)
+
+	// Write
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameClipboardEventWrittenBookmark, TargetID: targetIDClipboard})
+	}
+	err = cb.WriteBookmark("title", "http://url")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameClipboardCmdWriteBookmark + "\",\"targetID\":\"clipboard\",\"title\":\"title\",\"callbackId\":\"2\",\"url\":\"http://url\"}\n"}, wrt.w)
+
+	// Image
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "3", Name: EventNameClipboardEventWrittenImage, TargetID: targetIDClipboard})
+	}
+	err = cb.WriteImage([]byte("png"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameClipboardCmdWriteImage + "\",\"targetID\":\"clipboard\",\"callbackId\":\"3\",\"data\":\"cG5n\"}\n"}, wrt.w)
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Count: PtrInt(1), Name: EventNameClipboardEventReadImage, TargetID: targetIDClipboard})
+		d.dispatch(Event{CallbackID: "4", Data: []byte("png"), Index: PtrInt(0), Name: EventNameClipboardEventReadImage, TargetID: targetIDClipboard})
+	}
+	b, err := cb.ReadImage()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("png"), b)
+
+	// Error
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "5", Error: "not supported", Name: EventNameClipboardEventReadBookmark, TargetID: targetIDClipboard})
+	}
+	_, _, err = cb.ReadBookmark()
+	assert.EqualError(t, err, "not supported")
+}
+
+func TestClipboard_Watch(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	defer c.Cancel()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var m sync.Mutex
+	var text = "1"
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var cb = newClipboard(c, d, i, w, targetIDClipboard)
+	var cbs = newClipboard(c, d, i, w, targetIDClipboardSelection)
+	wrt.fn = func() {
+		m.Lock()
+		defer m.Unlock()
+		i.m.Lock()
+		var id = strconv.Itoa(i.i)
+		i.m.Unlock()
+		switch {
+		case len(wrt.w)%3 == 1:
+			d.dispatch(Event{CallbackID: id, Formats: []string{"text/plain"}, Name: EventNameClipboardEventAvailableFormats, TargetID: targetIDClipboard})
+		case len(wrt.w)%3 == 2:
+			d.dispatch(Event{CallbackID: id, Name: EventNameClipboardEventReadText, TargetID: targetIDClipboard, Text: text})
+		default:
+			d.dispatch(Event{CallbackID: id, Name: EventNameClipboardEventReadHTML, TargetID: targetIDClipboard})
+		}
+	}
+	var ch = make(chan Event, 1)
+	cb.On(EventNameClipboardEventChanged, func(e Event) (deleteListener bool) {
+		ch <- e
+		return true
+	})
+	cbs.On(EventNameClipboardEventChanged, func(e Event) (deleteListener bool) {
+		t.Error("selection clipboard shouldn't be notified")
+		return
+	})
+
+	// Invalid interval
+	cbs.Watch(0)()
+
+	// Watch
+	var stop = cb.Watch(time.Millisecond)
+	defer stop()
+	time.Sleep(20 * time.Millisecond)
+	m.Lock()
+	text = "2"
+	m.Unlock()
+	select {
+	case e := <-ch:
+		assert.Equal(t, "2", e.Text)
+		assert.Equal(t, []string{"text/plain"}, e.Formats)
+	case <-time.After(time.Second):
+		t.Error("no changed event received")
+	}
+}
@@ -0,0 +1,110 @@
+package astilectron
+
+// Cookies event names
+const (
+	EventNameSessionCmdFlushCookies     = "session.cmd.flush.cookies"
+	EventNameSessionCmdGetCookies       = "session.cmd.get.cookies"
+	EventNameSessionCmdRemoveCookie     = "session.cmd.remove.cookie"
+	EventNameSessionCmdSetCookie        = "session.cmd.set.cookie"
+	EventNameSessionEventCookieChanged  = "session.event.cookie.changed"
+	EventNameSessionEventFlushedCookies = "session.event.flushed.cookies"
+	EventNameSessionEventGetCookies     = "session.event.get.cookies"
+	EventNameSessionEventRemovedCookie  = "session.event.removed.cookie"
+	EventNameSessionEventSetCookie      = "session.event.set.cookie"
+)
+
+// Cookie changed causes
+const (
+	CookieChangedCauseEvicted          = "evicted"
+	CookieChangedCauseExpired          = "expired"
+	CookieChangedCauseExpiredOverwrite = "expired-overwrite"
+	CookieChangedCauseExplicit         = "explicit"
+	CookieChangedCauseOverwrite        = "overwrite"
+)
+
+// Cookie represents a cookie
+// URL is only used when setting a cookie
+// ExpirationDate is a number of seconds since the UNIX epoch, session cookies don't have one
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/structures/cookie.md
+type Cookie struct {
+	Domain         string   `json:"domain,omitempty"`
+	ExpirationDate *float64 `json:"expirationDate,omitempty"`
+	HostOnly       *bool    `json:"hostOnly,omitempty"`
+	HTTPOnly       *bool    `json:"httpOnly,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Path           string   `json:"path,omitempty"`
+	Secure         *bool    `json:"secure,omitempty"`
+	Session        *bool    `json:"session,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	Value          string   `json:"value,omitempty"`
+}
+
+// CookieFilter represents a cookie filter
+// Empty fields match all cookies
+type CookieFilter struct {
+	Domain  string `json:"domain,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Secure  *bool  `json:"secure,omitempty"`
+	Session *bool  `json:"session,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Cookies represents the cookies of a session
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/cookies.md
+type Cookies struct {
+	*object
+	partition string
+}
+
+// sendEvent sends a cookies event and waits for its done event
+func (c *Cookies) sendEvent(i Event, eventNameDone string) (o Event, err error) {
+	if err = c.isActionable(); err != nil {
+		return
+	}
+	i.CallbackID = c.i.new()
+	i.Partition = PtrStr(c.partition)
+	i.TargetID = c.id
+	o, err = synchronousEventWithContext(c.ctx, c.c, c, c.w, i, eventNameDone)
+	return
+}
+
+// Flush writes any unwritten cookies data to disk
+func (c *Cookies) Flush() (err error) {
+	_, err = c.sendEvent(Event{Name: EventNameSessionCmdFlushCookies}, EventNameSessionEventFlushedCookies)
+	return
+}
+
+// Get returns the cookies matching the filter
+func (c *Cookies) Get(f CookieFilter) (cs []Cookie, err error) {
+	var e Event
+	if e, err = c.sendEvent(Event{CookieFilter: &f, Name: EventNameSessionCmdGetCookies}, EventNameSessionEventGetCookies); err != nil {
+		return
+	}
+	cs = e.Cookies
+	return
+}
+
+// Remove removes the cookies matching the url and name
+func (c *Cookies) Remove(url, name string) (err error) {
+	_, err = c.sendEvent(Event{Cookie: &Cookie{Name: name, URL: url}, Name: EventNameSessionCmdRemoveCookie}, EventNameSessionEventRemovedCookie)
+	return
+}
+
+// Set sets a cookie, overwriting any equivalent cookie
+// Cookie.URL is mandatory
+func (c *Cookies) Set(cookie Cookie) (err error) {
+	_, err = c.sendEvent(Event{Cookie: &cookie, Name: EventNameSessionCmdSetCookie}, EventNameSessionEventSetCookie)
+	return
+}
+
+// OnCookieChanged adds a listener executed whenever a cookie of the session is added, edited, removed or expired
+// cause is one of the CookieChangedCause constants
+func (s *Session) OnCookieChanged(fn func(c Cookie, cause string, removed bool)) {
+	s.On(EventNameSessionEventCookieChanged, func(e Event) (deleteListener bool) {
+		if e.Cookie != nil {
+			fn(*e.Cookie, e.Cause, e.Removed != nil && *e.Removed)
+		}
+		return
+	})
+}
@@ -0,0 +1,245 @@
+package astilectron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/asticode/go-astilog"
+	"github.com/pkg/errors"
+)
+
+// Credential provider event names
+const (
+	EventNameAppCmdInterceptLogin   = "app.cmd.intercept.login"
+	EventNameAppCmdLoginCallback    = "app.cmd.login.callback"
+	EventNameAppCmdUninterceptLogin = "app.cmd.unintercept.login"
+	EventNameAppEventLogin          = "app.event.login"
+	EventNameAppEventLoginSucceeded = "app.event.login.succeeded"
+)
+
+// Default credential max attempts
+const DefaultCredentialMaxAttempts = 3
+
+// Vars
+var (
+	ErrCredentialsCancelled = errors.New("credentials.cancelled")
+)
+
+// CredentialRequest represents an HTTP or proxy authentication request
+// WindowID is empty when the request isn't tied to a window, e.g. for session downloads. Attempt starts at 1 and is
+// increased each time the previous credentials were wrong.
+type CredentialRequest struct {
+	Attempt   int
+	AuthInfo  EventAuthInfo
+	Partition string
+	URL       string
+	WindowID  string
+}
+
+// Credentials represents credentials
+type Credentials struct {
+	Password string
+	Username string
+}
+
+// CredentialProvider provides credentials for HTTP and proxy authentications
+// Return ErrCredentialsCancelled or empty credentials to cancel the authentication
+type CredentialProvider interface {
+	Credentials(ctx context.Context, r CredentialRequest) (c Credentials, err error)
+}
+
+// credentialEntry represents the credentials state of a realm
+type credentialEntry struct {
+	accepted *Credentials // Last credentials Electron has reported as accepted
+	answers  int          // Number of credentials sent since the realm's last successful or cancelled authentication
+	m        sync.Mutex
+	sent     *Credentials // Last credentials sent
+}
+
+// credentialKey returns the key used to index credentials, one per realm and session partition
+func credentialKey(i EventAuthInfo, partition string) string {
+	var port int
+	if i.Port != nil {
+		port = *i.Port
+	}
+	return fmt.Sprintf("%s|%t|%s|%s|%d|%s", partition, i.IsProxy != nil && *i.IsProxy, i.Scheme, i.Host, port, i.Realm)
+}
+
+// SetCredentialProvider sets the provider answering the HTTP and proxy authentication requests of the app, nil stops
+// forwarding them to GO
+// Credentials Electron reports as accepted are cached per realm and session partition and sent again without asking the
+// provider, e.g. once the auth cache has been cleared. Until then, a new request for a realm that has already been
+// answered means the previous credentials were wrong, and the authentication is cancelled after
+// Options.CredentialMaxAttempts wrong credentials. Requests of windows having an OnLogin handler are left to that
+// handler.
+func (a *Astilectron) SetCredentialProvider(p CredentialProvider) (err error) {
+	a.m.Lock()
+	a.credentialProvider = p
+	a.credentials = make(map[string]*credentialEntry)
+	var started = a.writer != nil
+	a.m.Unlock()
+	if started {
+		var n = EventNameAppCmdInterceptLogin
+		if p == nil {
+			n = EventNameAppCmdUninterceptLogin
+		}
+		err = a.writer.write(Event{Name: n, TargetID: targetIDApp})
+	}
+	return
+}
+
+// interceptLogin asks Electron to forward authentication requests to GO if there's a credential provider
+func (a *Astilectron) interceptLogin() (err error) {
+	a.m.Lock()
+	var intercept = a.credentialProvider != nil
+	a.m.Unlock()
+	if intercept {
+		err = a.writer.write(Event{Name: EventNameAppCmdInterceptLogin, TargetID: targetIDApp})
+	}
+	return
+}
+
+// credentialMaxAttempts returns the number of wrong credentials after which the authentication is cancelled
+func (a *Astilectron) credentialMaxAttempts() int {
+	if a.options.CredentialMaxAttempts > 0 {
+		return a.options.CredentialMaxAttempts
+	}
+	return DefaultCredentialMaxAttempts
+}
+
+// onLogin answers the authentication request with the credential provider
+// Electron always waits for the answer, which has no allow field when the window's OnLogin handler answers the
+// request instead
+func (a *Astilectron) onLogin(e Event) (deleteListener bool) {
+	// Get credentials
+	var c *Credentials
+	var deferred = len(e.WindowID) > 0 && len(a.dispatcher.listeners(e.WindowID, EventNameWebContentsEventLogin)) > 0
+	if !deferred {
+		var r = a.credentialRequest(e)
+		a.m.Lock()
+		var p = a.credentialProvider
+		var ce *credentialEntry
+		if p != nil {
+			ce = a.credentialEntry(r)
+		}
+		a.m.Unlock()
+		if ce != nil {
+			c = a.credentialsFromEntry(ce, p, r)
+		}
+	}
+
+	// Send message back
+	var cb = Event{CallbackID: e.CallbackID, Name: EventNameAppCmdLoginCallback, TargetID: targetIDApp}
+	if !deferred {
+		cb.Allow = PtrBool(c != nil)
+	}
+	if c != nil {
+		cb.Password = c.Password
+		cb.Username = c.Username
+	}
+	if err := a.writer.write(cb); err != nil {
+		astilog.Error(errors.Wrap(err, "writing login callback message failed"))
+	}
+	return
+}
+
+// onLoginSucceeded caches the credentials Electron has accepted and starts attempts over
+func (a *Astilectron) onLoginSucceeded(e Event) (deleteListener bool) {
+	var r = a.credentialRequest(e)
+	a.m.Lock()
+	var ce *credentialEntry
+	if a.credentialProvider != nil {
+		ce = a.credentialEntry(r)
+	}
+	a.m.Unlock()
+	if ce == nil {
+		return
+	}
+	ce.m.Lock()
+	defer ce.m.Unlock()
+	if ce.sent != nil {
+		ce.accepted = ce.sent
+	}
+	ce.answers = 0
+	return
+}
+
+// credentialRequest creates the credential request of an authentication event
+func (a *Astilectron) credentialRequest(e Event) (r CredentialRequest) {
+	r = CredentialRequest{URL: e.URL, WindowID: e.WindowID}
+	if e.AuthInfo != nil {
+		r.AuthInfo = *e.AuthInfo
+	}
+	if e.Partition != nil {
+		r.Partition = *e.Partition
+	}
+	return
+}
+
+// credentialEntry returns the credentials state of the request's realm
+// Assumes the mutex is locked
+func (a *Astilectron) credentialEntry(r CredentialRequest) (ce *credentialEntry) {
+	var k = credentialKey(r.AuthInfo, r.Partition)
+	var ok bool
+	if ce, ok = a.credentials[k]; !ok {
+		ce = &credentialEntry{}
+		a.credentials[k] = ce
+	}
+	return
+}
+
+// credentialsFromEntry asks the provider for credentials, the previous ones having been wrong if the realm has
+// already been answered since its last successful authentication
+// Nil is returned if the authentication must be cancelled
+func (a *Astilectron) credentialsFromEntry(ce *credentialEntry, p CredentialProvider, r CredentialRequest) (c *Credentials) {
+	// Requests of the same realm wait for each other so that attempts are counted properly
+	ce.m.Lock()
+	defer ce.m.Unlock()
+
+	// The previous credentials were wrong
+	if ce.answers > 0 {
+		ce.accepted = nil
+	}
+
+	// Too many wrong credentials
+	if ce.answers >= a.credentialMaxAttempts() {
+		astilog.Debugf("Cancelling authentication to %s after %d wrong credentials", r.URL, ce.answers)
+		ce.answers = 0
+		ce.sent = nil
+		return
+	}
+
+	// Use accepted credentials
+	if ce.accepted != nil {
+		c = ce.accepted
+		ce.answers++
+		ce.sent = c
+		return
+	}
+
+	// Ask provider
+	r.Attempt = ce.answers + 1
+	var ctx, cancel = a.canceller.NewContext()
+	defer cancel()
+	nc, err := p.Credentials(ctx, r)
+	if err != nil {
+		if err != ErrCredentialsCancelled {
+			astilog.Error(errors.Wrap(err, "getting credentials failed"))
+		}
+		ce.answers = 0
+		ce.sent = nil
+		return
+	}
+	if len(nc.Username) == 0 && len(nc.Password) == 0 {
+		ce.answers = 0
+		ce.sent = nil
+		return
+	}
+
+	// Answer
+	c = &nc
+	ce.answers++
+	ce.sent = c
+	return
+}
@@ -0,0 +1,114 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockedCredentialProvider struct {
+	m  sync.Mutex
+	rs []CredentialRequest
+}
+
+func (p *mockedCredentialProvider) Credentials(ctx context.Context, r CredentialRequest) (c Credentials, err error) {
+	p.m.Lock()
+	p.rs = append(p.rs, r)
+	p.m.Unlock()
+	if r.AuthInfo.Realm == "cancel" {
+		err = ErrCredentialsCancelled
+		return
+	}
+	c = Credentials{Password: "password", Username: "username"}
+	return
+}
+
+func TestAstilectron_SetCredentialProvider(t *testing.T) {
+	a, err := New(Options{CredentialMaxAttempts: 2})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{wg: &sync.WaitGroup{}}
+	a.writer = newWriter(wrt)
+	var login = func(callbackID, windowID, realm string) string {
+		wrt.w = []string{}
+		wrt.wg.Add(1)
+		a.dispatcher.dispatch(Event{AuthInfo: &EventAuthInfo{Host: "proxy", IsProxy: PtrBool(true), Port: PtrInt(8080), Realm: realm}, CallbackID: callbackID, Name: EventNameAppEventLogin, TargetID: targetIDApp, URL: "http://url", WindowID: windowID})
+		wrt.wg.Wait()
+		return wrt.w[0]
+	}
+	var cancelled = func(callbackID string) string {
+		return "{\"name\":\"" + EventNameAppCmdLoginCallback + "\",\"targetID\":\"app\",\"allow\":false,\"callbackId\":\"" + callbackID + "\"}\n"
+	}
+
+	// Set
+	p := &mockedCredentialProvider{}
+	wrt.wg.Add(1)
+	err = a.SetCredentialProvider(p)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdInterceptLogin + "\",\"targetID\":\"app\"}\n"}, wrt.w)
+
+	// Provider is asked
+	assert.Equal(t, "{\"name\":\""+EventNameAppCmdLoginCallback+"\",\"targetID\":\"app\",\"allow\":true,\"callbackId\":\"1\",\"password\":\"password\",\"username\":\"username\"}\n", login("1", "", "realm"))
+	assert.Len(t, p.rs, 1)
+	assert.Equal(t, 1, p.rs[0].Attempt)
+	assert.Equal(t, "proxy", p.rs[0].AuthInfo.Host)
+
+	// Wrong credentials
+	login("2", "", "realm")
+	assert.Len(t, p.rs, 2)
+	assert.Equal(t, 2, p.rs[1].Attempt)
+	assert.Equal(t, cancelled("3"), login("3", "", "realm"))
+	assert.Len(t, p.rs, 2)
+
+	// Attempts start over once the authentication has been cancelled
+	login("4", "", "realm")
+	assert.Len(t, p.rs, 3)
+	assert.Equal(t, 1, p.rs[2].Attempt)
+
+	// Explicit cancel
+	assert.Equal(t, cancelled("5"), login("5", "", "cancel"))
+	assert.Len(t, p.rs, 4)
+
+	// Accepted credentials are cached
+	login("6", "", "realm")
+	assert.Len(t, p.rs, 5)
+	wrt.w = []string{}
+	a.dispatcher.dispatch(Event{AuthInfo: &EventAuthInfo{Host: "proxy", IsProxy: PtrBool(true), Port: PtrInt(8080), Realm: "realm"}, Name: EventNameAppEventLoginSucceeded, TargetID: targetIDApp})
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "{\"name\":\""+EventNameAppCmdLoginCallback+"\",\"targetID\":\"app\",\"allow\":true,\"callbackId\":\"7\",\"password\":\"password\",\"username\":\"username\"}\n", login("7", "", "realm"))
+	assert.Len(t, p.rs, 5)
+
+	// Cached credentials are forgotten once they're wrong
+	login("8", "", "realm")
+	assert.Len(t, p.rs, 6)
+	assert.Equal(t, 2, p.rs[5].Attempt)
+
+	// Realms are independent across partitions
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{AuthInfo: &EventAuthInfo{Host: "proxy", IsProxy: PtrBool(true), Port: PtrInt(8080), Realm: "realm"}, CallbackID: "9", Name: EventNameAppEventLogin, Partition: PtrStr("persist:other"), TargetID: targetIDApp, URL: "http://url"})
+	wrt.wg.Wait()
+	assert.Len(t, p.rs, 7)
+	assert.Equal(t, 1, p.rs[6].Attempt)
+	assert.Equal(t, "persist:other", p.rs[6].Partition)
+
+	// Window handlers take precedence
+	a.dispatcher.addListener("6", EventNameWebContentsEventLogin, func(e Event) (deleteListener bool) { return })
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{AuthInfo: &EventAuthInfo{Realm: "realm"}, CallbackID: "10", Name: EventNameAppEventLogin, TargetID: targetIDApp, WindowID: "6"})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdLoginCallback + "\",\"targetID\":\"app\",\"callbackId\":\"10\"}\n"}, wrt.w)
+	assert.Len(t, p.rs, 7)
+
+	// Unset
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	err = a.SetCredentialProvider(nil)
+	assert.NoError(t, err)
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameAppCmdUninterceptLogin + "\",\"targetID\":\"app\"}\n"}, wrt.w)
+}
@@ -2,6 +2,26 @@
 
 package astilectron
 
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Dialog event names
+const (
+	EventNameDialogCmdShowCertificateTrustDialog     = "dialog.cmd.show.certificate.trust.dialog"
+	EventNameDialogCmdShowErrorBox                   = "dialog.cmd.show.error.box"
+	EventNameDialogCmdShowMessageBox                 = "dialog.cmd.show.message.box"
+	EventNameDialogCmdShowOpenDialog                 = "dialog.cmd.show.open.dialog"
+	EventNameDialogCmdShowSaveDialog                 = "dialog.cmd.show.save.dialog"
+	EventNameDialogEventCertificateTrustDialogClosed = "dialog.event.certificate.trust.dialog.closed"
+	EventNameDialogEventErrorBoxClosed               = "dialog.event.error.box.closed"
+	EventNameDialogEventMessageBoxClosed             = "dialog.event.message.box.closed"
+	EventNameDialogEventOpenDialogClosed             = "dialog.event.open.dialog.closed"
+	EventNameDialogEventSaveDialogClosed             = "dialog.event.save.dialog.closed"
+)
+
 // Message box types
 const (
 	MessageBoxTypeError    = "error"
@@ -29,3 +49,138 @@ type MessageBoxOptions struct {
 	Title           string   `json:"title,omitempty"`
 	Type            string   `json:"type,omitempty"`
 }
+
+// Open dialog properties
+const (
+	OpenDialogPropertyCreateDirectory         = "createDirectory"
+	OpenDialogPropertyMultiSelections         = "multiSelections"
+	OpenDialogPropertyNoResolveAliases        = "noResolveAliases"
+	OpenDialogPropertyOpenDirectory           = "openDirectory"
+	OpenDialogPropertyOpenFile                = "openFile"
+	OpenDialogPropertyPromptToCreate          = "promptToCreate"
+	OpenDialogPropertyShowHiddenFiles         = "showHiddenFiles"
+	OpenDialogPropertyTreatPackageAsDirectory = "treatPackageAsDirectory"
+)
+
+// CertificateTrustDialogOptions represents certificate trust dialog options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/dialog.md#dialogshowcertificatetrustdialogbrowserwindow-options-callback-macos-windows
+type CertificateTrustDialogOptions struct {
+	Certificate *Certificate `json:"certificate,omitempty"`
+	Message     string       `json:"message,omitempty"`
+}
+
+// FileFilter represents a file filter
+// Extensions must not contain wildcards or dots, "*" matching all files
+type FileFilter struct {
+	Extensions []string `json:"extensions,omitempty"`
+	Name       string   `json:"name,omitempty"`
+}
+
+// OpenDialogOptions represents open dialog options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/dialog.md#dialogshowopendialogbrowserwindow-options-callback
+type OpenDialogOptions struct {
+	ButtonLabel string       `json:"buttonLabel,omitempty"`
+	DefaultPath string       `json:"defaultPath,omitempty"`
+	Filters     []FileFilter `json:"filters,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	Properties  []string     `json:"properties,omitempty"`
+	Title       string       `json:"title,omitempty"`
+}
+
+// SaveDialogOptions represents save dialog options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/dialog.md#dialogshowsavedialogbrowserwindow-options-callback
+type SaveDialogOptions struct {
+	ButtonLabel    string       `json:"buttonLabel,omitempty"`
+	DefaultPath    string       `json:"defaultPath,omitempty"`
+	Filters        []FileFilter `json:"filters,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	NameFieldLabel string       `json:"nameFieldLabel,omitempty"`
+	ShowsTagField  *bool        `json:"showsTagField,omitempty"`
+	Title          string       `json:"title,omitempty"`
+}
+
+// dialogEvent returns a dialog event, modal to the parent window if any
+func (a *Astilectron) dialogEvent(name string, parent *Window) (e Event, err error) {
+	e = Event{CallbackID: a.identifier.new(), Name: name, TargetID: targetIDApp}
+	if parent != nil {
+		if err = parent.isActionable(); err != nil {
+			err = errors.Wrap(err, "parent window is not actionable")
+			return
+		}
+		e.WindowID = parent.id
+	}
+	return
+}
+
+// ShowCertificateTrustDialog shows a modal dialog asking the user to trust the certificate
+// It is only available on macOS and Windows
+func (a *Astilectron) ShowCertificateTrustDialog(ctx context.Context, parent *Window, o CertificateTrustDialogOptions) (err error) {
+	var e Event
+	if e, err = a.dialogEvent(EventNameDialogCmdShowCertificateTrustDialog, parent); err != nil {
+		return
+	}
+	e.Certificate = o.Certificate
+	e.Text = o.Message
+	_, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, e, EventNameDialogEventCertificateTrustDialogClosed)
+	return
+}
+
+// ShowErrorBox shows a modal dialog displaying an error message
+func (a *Astilectron) ShowErrorBox(ctx context.Context, title, content string) (err error) {
+	var e Event
+	if e, err = a.dialogEvent(EventNameDialogCmdShowErrorBox, nil); err != nil {
+		return
+	}
+	e.Text = content
+	e.Title = title
+	_, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, e, EventNameDialogEventErrorBoxClosed)
+	return
+}
+
+// ShowMessageBox shows a message box, modal to the parent window if any, and returns the index of the clicked button
+// as well as the checkbox state
+func (a *Astilectron) ShowMessageBox(ctx context.Context, parent *Window, o MessageBoxOptions) (button int, checkboxChecked bool, err error) {
+	var e Event
+	if e, err = a.dialogEvent(EventNameDialogCmdShowMessageBox, parent); err != nil {
+		return
+	}
+	e.MessageBoxOptions = &o
+	if e, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, e, EventNameDialogEventMessageBoxClosed); err != nil {
+		return
+	}
+	if e.Response != nil {
+		button = *e.Response
+	}
+	checkboxChecked = e.CheckboxChecked != nil && *e.CheckboxChecked
+	return
+}
+
+// ShowOpenDialog shows an open dialog, modal to the parent window if any, and returns the selected paths
+// No path is returned if the user cancelled the dialog
+func (a *Astilectron) ShowOpenDialog(ctx context.Context, parent *Window, o OpenDialogOptions) (paths []string, err error) {
+	var e Event
+	if e, err = a.dialogEvent(EventNameDialogCmdShowOpenDialog, parent); err != nil {
+		return
+	}
+	e.OpenDialogOptions = &o
+	if e, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, e, EventNameDialogEventOpenDialogClosed); err != nil {
+		return
+	}
+	paths = e.FilePaths
+	return
+}
+
+// ShowSaveDialog shows a save dialog, modal to the parent window if any, and returns the selected path
+// An empty path is returned if the user cancelled the dialog
+func (a *Astilectron) ShowSaveDialog(ctx context.Context, parent *Window, o SaveDialogOptions) (path string, err error) {
+	var e Event
+	if e, err = a.dialogEvent(EventNameDialogCmdShowSaveDialog, parent); err != nil {
+		return
+	}
+	e.SaveDialogOptions = &o
+	if e, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, e, EventNameDialogEventSaveDialogClosed); err != nil {
+		return
+	}
+	path = e.FilePath
+	return
+}
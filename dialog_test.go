@@ -0,0 +1,63 @@
+package astilectron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAstilectron_Dialogs(t *testing.T) {
+	// Init
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Message box
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "3", CheckboxChecked: PtrBool(true), Name: EventNameDialogEventMessageBoxClosed, Response: PtrInt(1), TargetID: targetIDApp})
+	}
+	button, checked, err := a.ShowMessageBox(context.Background(), w, MessageBoxOptions{Buttons: []string{"Yes", "No"}, Message: "message"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, button)
+	assert.True(t, checked)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameDialogCmdShowMessageBox + "\",\"targetID\":\"app\",\"callbackId\":\"3\",\"messageBoxOptions\":{\"buttons\":[\"Yes\",\"No\"],\"message\":\"message\"},\"windowId\":\"" + w.id + "\"}\n"}, wrt.w)
+
+	// Open dialog
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "4", FilePaths: []string{"/path/1", "/path/2"}, Name: EventNameDialogEventOpenDialogClosed, TargetID: targetIDApp})
+	}
+	paths, err := a.ShowOpenDialog(context.Background(), nil, OpenDialogOptions{Filters: []FileFilter{{Extensions: []string{"png"}, Name: "Images"}}, Properties: []string{OpenDialogPropertyOpenFile, OpenDialogPropertyMultiSelections}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/path/1", "/path/2"}, paths)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameDialogCmdShowOpenDialog + "\",\"targetID\":\"app\",\"callbackId\":\"4\",\"openDialogOptions\":{\"filters\":[{\"extensions\":[\"png\"],\"name\":\"Images\"}],\"properties\":[\"openFile\",\"multiSelections\"]}}\n"}, wrt.w)
+
+	// Save dialog
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "5", FilePath: "/path/to/file", Name: EventNameDialogEventSaveDialogClosed, TargetID: targetIDApp})
+	}
+	path, err := a.ShowSaveDialog(context.Background(), nil, SaveDialogOptions{DefaultPath: "file"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/path/to/file", path)
+
+	// Error box
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "6", Name: EventNameDialogEventErrorBoxClosed, TargetID: targetIDApp})
+	}
+	err = a.ShowErrorBox(context.Background(), "title", "content")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameDialogCmdShowErrorBox + "\",\"targetID\":\"app\",\"title\":\"title\",\"callbackId\":\"6\",\"text\":\"content\"}\n"}, wrt.w)
+
+	// Certificate trust dialog
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "7", Error: "not supported", Name: EventNameDialogEventCertificateTrustDialogClosed, TargetID: targetIDApp})
+	}
+	err = a.ShowCertificateTrustDialog(context.Background(), nil, CertificateTrustDialogOptions{Certificate: &Certificate{Fingerprint: "fingerprint"}, Message: "message"})
+	assert.EqualError(t, err, "not supported")
+}
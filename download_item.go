@@ -0,0 +1,117 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astitools/context"
+)
+
+// Download item event names
+const (
+	EventNameDownloadItemCmdCancel      = "download.item.cmd.cancel"
+	EventNameDownloadItemCmdPause       = "download.item.cmd.pause"
+	EventNameDownloadItemCmdResume      = "download.item.cmd.resume"
+	EventNameDownloadItemEventCancelled = "download.item.event.cancelled"
+	EventNameDownloadItemEventDone      = "download.item.event.done"
+	EventNameDownloadItemEventPaused    = "download.item.event.paused"
+	EventNameDownloadItemEventResumed   = "download.item.event.resumed"
+	EventNameDownloadItemEventUpdated   = "download.item.event.updated"
+)
+
+// Download item states
+const (
+	DownloadItemStateCancelled   = "cancelled"
+	DownloadItemStateCompleted   = "completed"
+	DownloadItemStateInterrupted = "interrupted"
+	DownloadItemStateProgressing = "progressing"
+)
+
+// DefaultDownloadHandlerTimeout represents the maximum duration of OnDownload handlers, the download is cancelled
+// once it is reached
+const DefaultDownloadHandlerTimeout = 10 * time.Second
+
+// DownloadDecision represents the decision of an OnDownload handler
+type DownloadDecision struct {
+	Cancel   bool
+	SavePath string // The user is prompted for a save path when empty
+}
+
+// DownloadItemInfo represents a download item info
+type DownloadItemInfo struct {
+	CanResume     *bool  `json:"canResume,omitempty"`
+	Filename      string `json:"filename,omitempty"`
+	IsPaused      *bool  `json:"isPaused,omitempty"`
+	MimeType      string `json:"mimeType,omitempty"`
+	ReceivedBytes int64  `json:"receivedBytes,omitempty"`
+	SavePath      string `json:"savePath,omitempty"`
+	State         string `json:"state,omitempty"`
+	TotalBytes    int64  `json:"totalBytes,omitempty"`
+	URL           string `json:"url,omitempty"`
+}
+
+// DownloadItem represents a download item
+// Its ID is given by Electron when the download starts.
+// Listen to EventNameDownloadItemEventUpdated and EventNameDownloadItemEventDone events with On to follow its progress
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/download-item.md
+type DownloadItem struct {
+	*object
+	done bool
+	info DownloadItemInfo
+	m    sync.Mutex // Locks done and info
+}
+
+// newDownloadItem creates a new download item
+func newDownloadItem(parentCtx context.Context, c *asticontext.Canceller, d *dispatcher, i *identifier, w *writer, id string, info DownloadItemInfo) *DownloadItem {
+	return &DownloadItem{info: info, object: newObject(parentCtx, c, d, i, w, id)}
+}
+
+// update updates the info with the one held by the event and returns false if the download is already done
+// Events are dispatched concurrently, therefore an update received after the done event is ignored
+func (di *DownloadItem) update(e Event) bool {
+	di.m.Lock()
+	defer di.m.Unlock()
+	if di.done {
+		return false
+	}
+	if e.DownloadItem != nil {
+		di.info = *e.DownloadItem
+	}
+	di.done = e.Name == EventNameDownloadItemEventDone
+	return true
+}
+
+// Info returns the latest download item info received
+func (di *DownloadItem) Info() DownloadItemInfo {
+	di.m.Lock()
+	defer di.m.Unlock()
+	return di.info
+}
+
+// Cancel cancels the download
+func (di *DownloadItem) Cancel() (err error) {
+	if err = di.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(di.ctx, di.c, di, di.w, Event{CallbackID: di.i.new(), Name: EventNameDownloadItemCmdCancel, TargetID: di.id}, EventNameDownloadItemEventCancelled)
+	return
+}
+
+// Pause pauses the download
+func (di *DownloadItem) Pause() (err error) {
+	if err = di.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(di.ctx, di.c, di, di.w, Event{CallbackID: di.i.new(), Name: EventNameDownloadItemCmdPause, TargetID: di.id}, EventNameDownloadItemEventPaused)
+	return
+}
+
+// Resume resumes the download
+func (di *DownloadItem) Resume() (err error) {
+	if err = di.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(di.ctx, di.c, di, di.w, Event{CallbackID: di.i.new(), Name: EventNameDownloadItemCmdResume, TargetID: di.id}, EventNameDownloadItemEventResumed)
+	return
+}
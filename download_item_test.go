@@ -0,0 +1,39 @@
+package astilectron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadItem(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var di = newDownloadItem(context.Background(), c, d, i, w, "download.1", DownloadItemInfo{})
+
+	// Actions
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "1", Name: EventNameDownloadItemEventPaused, TargetID: di.id})
+	}
+	err := di.Pause()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameDownloadItemCmdPause + "\",\"targetID\":\"download.1\",\"callbackId\":\"1\"}\n"}, wrt.w)
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameDownloadItemEventResumed, TargetID: di.id})
+	}
+	err = di.Resume()
+	assert.NoError(t, err)
+
+	// Update
+	assert.True(t, di.update(Event{DownloadItem: &DownloadItemInfo{ReceivedBytes: 10, State: DownloadItemStateProgressing}, Name: EventNameDownloadItemEventUpdated}))
+	assert.Equal(t, int64(10), di.Info().ReceivedBytes)
+	assert.True(t, di.update(Event{DownloadItem: &DownloadItemInfo{ReceivedBytes: 20, State: DownloadItemStateCompleted}, Name: EventNameDownloadItemEventDone}))
+	assert.False(t, di.update(Event{DownloadItem: &DownloadItemInfo{ReceivedBytes: 15, State: DownloadItemStateProgressing}, Name: EventNameDownloadItemEventUpdated}))
+	assert.Equal(t, DownloadItemInfo{ReceivedBytes: 20, State: DownloadItemStateCompleted}, di.Info())
+}
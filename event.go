@@ -7,8 +7,11 @@ import (
 
 // Target IDs
 const (
-	targetIDApp  = "app"
-	targetIDDock = "dock"
+	targetIDApp                = "app"
+	targetIDClipboard          = "clipboard"
+	targetIDClipboardSelection = "clipboard.selection"
+	targetIDDock               = "dock"
+	targetIDPowerMonitor       = "power.monitor"
 )
 
 // Event represents an event
@@ -20,38 +23,96 @@ type Event struct {
 	// This is a list of all possible payloads.
 	// A choice was made not to use interfaces since it's a pain in the ass asserting each an every payload afterwards
 	// We use pointers so that omitempty works
-	AuthInfo            *EventAuthInfo       `json:"authInfo,omitempty"`
-	Badge               string               `json:"badge,omitempty"`
-	BounceType          string               `json:"bounceType,omitempty"`
-	Title               string               `json:"title,omitempty"`
-	Bounds              *RectangleOptions    `json:"bounds,omitempty"`
-	CallbackID          string               `json:"callbackId,omitempty"`
-	Displays            *EventDisplays       `json:"displays,omitempty"`
-	FilePath            string               `json:"filePath,omitempty"`
-	ID                  *int                 `json:"id,omitempty"`
-	Image               string               `json:"image,omitempty"`
-	Index               *int                 `json:"index,omitempty"`
-	HookMessage         *int                 `json:"hookMessage,omitempty"`
-	Wparam              *int                 `json:"wparam,omitempty"`
-	Menu                *EventMenu           `json:"menu,omitempty"`
-	MenuItem            *EventMenuItem       `json:"menuItem,omitempty"`
-	MenuItemOptions     *MenuItemOptions     `json:"menuItemOptions,omitempty"`
-	MenuItemPosition    *int                 `json:"menuItemPosition,omitempty"`
-	MenuPopupOptions    *MenuPopupOptions    `json:"menuPopupOptions,omitempty"`
-	Message             *EventMessage        `json:"message,omitempty"`
-	NotificationOptions *NotificationOptions `json:"notificationOptions,omitempty"`
-	Password            string               `json:"password,omitempty"`
-	Reply               string               `json:"reply,omitempty"`
-	Request             *EventRequest        `json:"request,omitempty"`
-	SessionID           string               `json:"sessionId,omitempty"`
-	Supported           *Supported           `json:"supported,omitempty"`
-	TrayOptions         *TrayOptions         `json:"trayOptions,omitempty"`
-	URL                 string               `json:"url,omitempty"`
-	URLNew              string               `json:"newUrl,omitempty"`
-	URLOld              string               `json:"oldUrl,omitempty"`
-	Username            string               `json:"username,omitempty"`
-	WindowID            string               `json:"windowId,omitempty"`
-	WindowOptions       *WindowOptions       `json:"windowOptions,omitempty"`
+	Accelerator         *Accelerator                  `json:"accelerator,omitempty"`
+	Action              string                        `json:"action,omitempty"`
+	Allow               *bool                         `json:"allow,omitempty"`
+	AuthInfo            *EventAuthInfo                `json:"authInfo,omitempty"`
+	Badge               string                        `json:"badge,omitempty"`
+	BadgeCount          *int                          `json:"badgeCount,omitempty"`
+	BlockerType         string                        `json:"blockerType,omitempty"`
+	BounceType          string                        `json:"bounceType,omitempty"`
+	Title               string                        `json:"title,omitempty"`
+	Bounds              *RectangleOptions             `json:"bounds,omitempty"`
+	CacheSize           *int64                        `json:"cacheSize,omitempty"`
+	CallbackID          string                        `json:"callbackId,omitempty"`
+	CapturePageOptions  *CapturePageOptions           `json:"capturePageOptions,omitempty"`
+	Cause               string                        `json:"cause,omitempty"`
+	Certificate         *Certificate                  `json:"certificate,omitempty"`
+	CertificateError    *CertificateErrorEvent        `json:"certificateError,omitempty"`
+	CertificateSelect   *SelectClientCertificateEvent `json:"certificateSelect,omitempty"`
+	CertificateVerify   *CertificateVerifyRequest     `json:"certificateVerify,omitempty"`
+	CheckboxChecked     *bool                         `json:"checkboxChecked,omitempty"`
+	Cookie              *Cookie                       `json:"cookie,omitempty"`
+	CookieFilter        *CookieFilter                 `json:"cookieFilter,omitempty"`
+	Cookies             []Cookie                      `json:"cookies,omitempty"`
+	Count               *int                          `json:"count,omitempty"`
+	Data                []byte                        `json:"data,omitempty"`
+	Displays            *EventDisplays                `json:"displays,omitempty"`
+	DownloadItem        *DownloadItemInfo             `json:"downloadItem,omitempty"`
+	DownloadItemID      string                        `json:"downloadItemId,omitempty"`
+	Error               string                        `json:"error,omitempty"`
+	FilePath            string                        `json:"filePath,omitempty"`
+	FilePaths           []string                      `json:"filePaths,omitempty"`
+	FindInPageOptions   *FindInPageOptions            `json:"findInPageOptions,omitempty"`
+	Flash               *bool                         `json:"flash,omitempty"`
+	Formats             []string                      `json:"formats,omitempty"`
+	FoundInPage         *FoundInPageResult            `json:"foundInPage,omitempty"`
+	ID                  *int                          `json:"id,omitempty"`
+	IdleState           string                        `json:"idleState,omitempty"`
+	IdleThreshold       *int                          `json:"idleThreshold,omitempty"`
+	IdleTime            *int                          `json:"idleTime,omitempty"`
+	Image               string                        `json:"image,omitempty"`
+	Index               *int                          `json:"index,omitempty"`
+	HookMessage         *int                          `json:"hookMessage,omitempty"`
+	Wparam              *int                          `json:"wparam,omitempty"`
+	Menu                *EventMenu                    `json:"menu,omitempty"`
+	MenuItem            *EventMenuItem                `json:"menuItem,omitempty"`
+	MenuItemOptions     *MenuItemOptions              `json:"menuItemOptions,omitempty"`
+	MenuItemPosition    *int                          `json:"menuItemPosition,omitempty"`
+	MenuPopupOptions    *MenuPopupOptions             `json:"menuPopupOptions,omitempty"`
+	Message             *EventMessage                 `json:"message,omitempty"`
+	MessageBoxOptions   *MessageBoxOptions            `json:"messageBoxOptions,omitempty"`
+	NotificationOptions *NotificationOptions          `json:"notificationOptions,omitempty"`
+	OpenDialogOptions   *OpenDialogOptions            `json:"openDialogOptions,omitempty"`
+	ParentID            string                        `json:"parentId,omitempty"`
+	Partition           *string                       `json:"partition,omitempty"`
+	Password            string                        `json:"password,omitempty"`
+	PermissionRequest   *PermissionRequest            `json:"permissionRequest,omitempty"`
+	PrintOptions        *PrintOptions                 `json:"printOptions,omitempty"`
+	PrintToPDFOptions   *PrintToPDFOptions            `json:"printToPdfOptions,omitempty"`
+	ProgressBar         *ProgressBarOptions           `json:"progressBar,omitempty"`
+	ProtocolRequest     *ProtocolRequest              `json:"protocolRequest,omitempty"`
+	ProtocolResponse    *ProtocolResponse             `json:"protocolResponse,omitempty"`
+	Proxy               string                        `json:"proxy,omitempty"`
+	ProxyConfig         *ProxyConfig                  `json:"proxyConfig,omitempty"`
+	Registered          *bool                         `json:"registered,omitempty"`
+	Removed             *bool                         `json:"removed,omitempty"`
+	Reply               string                        `json:"reply,omitempty"`
+	Request             *EventRequest                 `json:"request,omitempty"`
+	Response            *int                          `json:"response,omitempty"`
+	SaveDialogOptions   *SaveDialogOptions            `json:"saveDialogOptions,omitempty"`
+	Scheme              string                        `json:"scheme,omitempty"`
+	SessionID           string                        `json:"sessionId,omitempty"`
+	StorageData         *StorageDataOptions           `json:"storageData,omitempty"`
+	Supported           *Supported                    `json:"supported,omitempty"`
+	Text                string                        `json:"text,omitempty"`
+	Timeout             *int                          `json:"timeout,omitempty"`
+	TimeoutPolicy       string                        `json:"timeoutPolicy,omitempty"`
+	TrayOptions         *TrayOptions                  `json:"trayOptions,omitempty"`
+	URL                 string                        `json:"url,omitempty"`
+	URLNew              string                        `json:"newUrl,omitempty"`
+	URLOld              string                        `json:"oldUrl,omitempty"`
+	Username            string                        `json:"username,omitempty"`
+	WebRequestDetails   *WebRequestDetails            `json:"webRequestDetails,omitempty"`
+	WebRequestFilter    *WebRequestFilter             `json:"webRequestFilter,omitempty"`
+	WebRequestResponse  *WebRequestResponse           `json:"webRequestResponse,omitempty"`
+	WindowID            string                        `json:"windowId,omitempty"`
+	WindowOptions       *WindowOptions                `json:"windowOptions,omitempty"`
+	WindowState         *WindowState                  `json:"windowState,omitempty"`
+	ZoomDirection       string                        `json:"zoomDirection,omitempty"`
+	ZoomFactor          *float64                      `json:"zoomFactor,omitempty"`
+	ZoomLevel           *float64                      `json:"zoomLevel,omitempty"`
+	ZoomLevelLimits     *ZoomLevelLimits              `json:"zoomLevelLimits,omitempty"`
 }
 
 // EventAuthInfo represents an event auth info
@@ -0,0 +1,87 @@
+package astilectron
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Global shortcut event names
+const (
+	EventNameGlobalShortcutCmdIsRegistered      = "global.shortcut.cmd.is.registered"
+	EventNameGlobalShortcutCmdRegister          = "global.shortcut.cmd.register"
+	EventNameGlobalShortcutCmdUnregister        = "global.shortcut.cmd.unregister"
+	EventNameGlobalShortcutCmdUnregisterAll     = "global.shortcut.cmd.unregister.all"
+	EventNameGlobalShortcutEventIsRegistered    = "global.shortcut.event.is.registered"
+	EventNameGlobalShortcutEventRegistered      = "global.shortcut.event.registered"
+	EventNameGlobalShortcutEventTriggered       = "global.shortcut.event.triggered"
+	EventNameGlobalShortcutEventUnregistered    = "global.shortcut.event.unregistered"
+	EventNameGlobalShortcutEventUnregisteredAll = "global.shortcut.event.unregistered.all"
+)
+
+// acceleratorKey returns the key used to index global shortcuts
+func acceleratorKey(a *Accelerator) string {
+	b, _ := a.MarshalText()
+	return string(b)
+}
+
+// onGlobalShortcutTriggered executes the listener of the triggered global shortcut
+func (a *Astilectron) onGlobalShortcutTriggered(e Event) (deleteListener bool) {
+	if e.Accelerator == nil {
+		return
+	}
+	a.m.Lock()
+	l, ok := a.globalShortcuts[acceleratorKey(e.Accelerator)]
+	a.m.Unlock()
+	if ok {
+		l(e)
+	}
+	return
+}
+
+// IsRegistered checks whether the global shortcut accelerator is registered
+// It returns false if the accelerator has been registered by another application
+func (a *Astilectron) IsRegistered(ctx context.Context, accelerator *Accelerator) (ok bool, err error) {
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{Accelerator: accelerator, CallbackID: a.identifier.new(), Name: EventNameGlobalShortcutCmdIsRegistered, TargetID: targetIDApp}, EventNameGlobalShortcutEventIsRegistered); err != nil {
+		return
+	}
+	ok = e.Registered != nil && *e.Registered
+	return
+}
+
+// RegisterGlobalShortcut registers a global shortcut executing the listener even when the app doesn't have the
+// keyboard focus
+// An error is returned if the accelerator is already taken by another application
+func (a *Astilectron) RegisterGlobalShortcut(ctx context.Context, accelerator *Accelerator, l Listener) (err error) {
+	if _, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{Accelerator: accelerator, CallbackID: a.identifier.new(), Name: EventNameGlobalShortcutCmdRegister, TargetID: targetIDApp}, EventNameGlobalShortcutEventRegistered); err != nil {
+		err = errors.Wrapf(err, "registering global shortcut %s failed", acceleratorKey(accelerator))
+		return
+	}
+	a.m.Lock()
+	a.globalShortcuts[acceleratorKey(accelerator)] = l
+	a.m.Unlock()
+	return
+}
+
+// UnregisterAll unregisters all the global shortcuts
+func (a *Astilectron) UnregisterAll(ctx context.Context) (err error) {
+	if _, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{CallbackID: a.identifier.new(), Name: EventNameGlobalShortcutCmdUnregisterAll, TargetID: targetIDApp}, EventNameGlobalShortcutEventUnregisteredAll); err != nil {
+		return
+	}
+	a.m.Lock()
+	a.globalShortcuts = make(map[string]Listener)
+	a.m.Unlock()
+	return
+}
+
+// UnregisterGlobalShortcut unregisters a global shortcut
+func (a *Astilectron) UnregisterGlobalShortcut(ctx context.Context, accelerator *Accelerator) (err error) {
+	if _, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{Accelerator: accelerator, CallbackID: a.identifier.new(), Name: EventNameGlobalShortcutCmdUnregister, TargetID: targetIDApp}, EventNameGlobalShortcutEventUnregistered); err != nil {
+		return
+	}
+	a.m.Lock()
+	delete(a.globalShortcuts, acceleratorKey(accelerator))
+	a.m.Unlock()
+	return
+}
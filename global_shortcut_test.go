@@ -0,0 +1,72 @@
+package astilectron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAstilectron_GlobalShortcuts(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	acc := NewAccelerator("CmdOrCtrl", "X")
+
+	// Register
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameGlobalShortcutEventRegistered, TargetID: targetIDApp})
+	}
+	var c int
+	err = a.RegisterGlobalShortcut(context.Background(), acc, func(e Event) (deleteListener bool) {
+		c++
+		return
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameGlobalShortcutCmdRegister + "\",\"targetID\":\"app\",\"accelerator\":\"CmdOrCtrl+X\",\"callbackId\":\"1\"}\n"}, wrt.w)
+	a.onGlobalShortcutTriggered(Event{Accelerator: NewAccelerator("CmdOrCtrl", "X"), Name: EventNameGlobalShortcutEventTriggered, TargetID: targetIDApp})
+	a.onGlobalShortcutTriggered(Event{Accelerator: NewAccelerator("CmdOrCtrl", "Y"), Name: EventNameGlobalShortcutEventTriggered, TargetID: targetIDApp})
+	assert.Equal(t, 1, c)
+
+	// Taken by another application
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Error: "taken", Name: EventNameGlobalShortcutEventRegistered, TargetID: targetIDApp})
+	}
+	err = a.RegisterGlobalShortcut(context.Background(), NewAccelerator("CmdOrCtrl", "Y"), func(e Event) (deleteListener bool) { return })
+	assert.Error(t, err)
+	assert.Len(t, a.globalShortcuts, 1)
+
+	// Is registered
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "3", Name: EventNameGlobalShortcutEventIsRegistered, Registered: PtrBool(true), TargetID: targetIDApp})
+	}
+	ok, err := a.IsRegistered(context.Background(), acc)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Unregister
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "4", Name: EventNameGlobalShortcutEventUnregistered, TargetID: targetIDApp})
+	}
+	err = a.UnregisterGlobalShortcut(context.Background(), acc)
+	assert.NoError(t, err)
+	assert.Len(t, a.globalShortcuts, 0)
+
+	// Unregister all
+	a.globalShortcuts["a"] = func(e Event) (deleteListener bool) { return }
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "5", Name: EventNameGlobalShortcutEventUnregisteredAll, TargetID: targetIDApp})
+	}
+	err = a.UnregisterAll(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, a.globalShortcuts, 0)
+
+	// Close unregisters remaining global shortcuts
+	a.globalShortcuts["a"] = func(e Event) (deleteListener bool) { return }
+	wrt.fn = nil
+	wrt.w = []string{}
+	a.Close()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameGlobalShortcutCmdUnregisterAll + "\",\"targetID\":\"app\"}\n"}, wrt.w)
+}
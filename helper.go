@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/asticode/go-astilog"
@@ -181,6 +183,34 @@ func PtrStr(i string) *string {
 	return &i
 }
 
+// closeDecision executes fn with a context timing out after the timeout and returns whether closing is allowed
+// If fn doesn't return in time, closing is allowed so that the app never gets stuck
+func closeDecision(timeout time.Duration, fn func(ctx context.Context) (allow bool)) (allow bool) {
+	return timedDecision(timeout, true, fn)
+}
+
+// timedDecision executes fn with a context timing out after the timeout and returns its decision
+// If fn doesn't return in time, the fallback decision is returned
+func timedDecision(timeout time.Duration, fallback bool, fn func(ctx context.Context) (allow bool)) (allow bool) {
+	return timedValue(timeout, fallback, func(ctx context.Context) interface{} { return fn(ctx) }).(bool)
+}
+
+// timedValue executes fn with a context timing out after the timeout and returns its value
+// If fn doesn't return in time, the fallback value is returned
+func timedValue(timeout time.Duration, fallback interface{}, fn func(ctx context.Context) interface{}) (v interface{}) {
+	var ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var ch = make(chan interface{}, 1)
+	go func() { ch <- fn(ctx) }()
+	select {
+	case v = <-ch:
+	case <-ctx.Done():
+		astilog.Debugf("No decision has been made in the past %s, falling back to %v", timeout, fallback)
+		v = fallback
+	}
+	return
+}
+
 // synchronousFunc 该函数执行fn()，然后 <作为监听者一直等到收到一个eventNameDone事件> 或 <被cancelled> 导致退出函数
 func synchronousFunc(c *asticontext.Canceller, l listenable, fn func(), eventNameDone string) (e Event) {
 	var ctx, cancel = c.NewContext()
@@ -207,3 +237,125 @@ func synchronousEvent(c *asticontext.Canceller, l listenable, w *writer, i Event
 	}, eventNameDone)
 	return
 }
+
+// synchronousEventWithContext sends an event and waits for the eventNameDone event holding the same callback ID, the
+// context to be done or the canceller to be cancelled. An error reported by the JS in the done event is returned.
+// Listeners can't be removed from outside the dispatcher, so once the function returns the listener deletes itself
+// on the next eventNameDone event instead of lingering forever.
+func synchronousEventWithContext(ctx context.Context, c *asticontext.Canceller, l listenable, w *writer, i Event, eventNameDone string) (o Event, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	var cctx, cancel = c.NewContext()
+	defer cancel()
+	var ch = make(chan Event, 1)
+	var done int32
+	defer atomic.StoreInt32(&done, 1)
+	l.On(eventNameDone, func(e Event) (deleteListener bool) {
+		if atomic.LoadInt32(&done) == 1 {
+			return true
+		}
+		if e.CallbackID != i.CallbackID {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+		return true
+	})
+	if err = w.write(i); err != nil {
+		err = errors.Wrapf(err, "writing %+v event failed", i)
+		return
+	}
+	select {
+	case o = <-ch:
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	case <-cctx.Done():
+		err = ErrCancellerCancelled
+		return
+	}
+	if len(o.Error) > 0 {
+		err = errors.New(o.Error)
+	}
+	return
+}
+
+// synchronousChunkedEvent sends an event and reassembles the data sent back by the JS in several eventNameDone events
+// holding the same callback ID.
+// Large payloads can't be sent in one line so the JS splits them into chunks holding an index, then sends a last event
+// holding the number of chunks. Since events are dispatched concurrently, chunks may be received in any order.
+func synchronousChunkedEvent(ctx context.Context, c *asticontext.Canceller, l listenable, w *writer, i Event, eventNameDone string) (b []byte, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	var cctx, cancel = c.NewContext()
+	defer cancel()
+	var chunks = make(map[int][]byte)
+	var count = -1
+	var ch = make(chan error, 1)
+	var m sync.Mutex
+	var done int32
+	defer atomic.StoreInt32(&done, 1)
+	l.On(eventNameDone, func(e Event) (deleteListener bool) {
+		if atomic.LoadInt32(&done) == 1 {
+			return true
+		}
+		if e.CallbackID != i.CallbackID {
+			return
+		}
+		m.Lock()
+		defer m.Unlock()
+		if len(e.Error) > 0 {
+			select {
+			case ch <- errors.New(e.Error):
+			default:
+			}
+			return true
+		}
+		if e.Index != nil {
+			chunks[*e.Index] = e.Data
+		} else if e.Count != nil {
+			count = *e.Count
+		}
+		if count >= 0 && len(chunks) >= count {
+			select {
+			case ch <- nil:
+			default:
+			}
+			return true
+		}
+		return
+	})
+	if err = w.write(i); err != nil {
+		err = errors.Wrapf(err, "writing %+v event failed", i)
+		return
+	}
+	select {
+	case err = <-ch:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-cctx.Done():
+		err = ErrCancellerCancelled
+	}
+	if err != nil {
+		return
+	}
+
+	// Reassemble
+	m.Lock()
+	defer m.Unlock()
+	var buf bytes.Buffer
+	for idx := 0; idx < count; idx++ {
+		v, ok := chunks[idx]
+		if !ok {
+			err = errors.Errorf("chunk %d is missing", idx)
+			return
+		}
+		buf.Write(v)
+	}
+	b = buf.Bytes()
+	return
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"fmt"
 
@@ -181,3 +182,70 @@ func TestSynchronousEvent(t *testing.T) {
 	assert.Equal(t, ed, e)
 	assert.Equal(t, []string{"{\"name\":\"order\",\"targetID\":\"1\"}\n"}, mw.w)
 }
+
+func TestSynchronousEventWithContext(t *testing.T) {
+	// Init
+	var d = newDispatcher()
+	var mw = &mockedWriter{}
+	var w = newWriter(mw)
+	var c = asticontext.NewCanceller()
+	var l = &mockedListenable{d: d, id: "1"}
+
+	// Test successful synchronous event
+	mw.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: "done", TargetID: "1"})
+		d.dispatch(Event{CallbackID: "1", Name: "done", TargetID: "1", URL: "url"})
+	}
+	var e, err = synchronousEventWithContext(context.Background(), c, l, w, Event{CallbackID: "1", Name: "order", TargetID: "1"}, "done")
+	assert.NoError(t, err)
+	assert.Equal(t, "url", e.URL)
+
+	// Test error
+	mw.fn = func() { d.dispatch(Event{CallbackID: "3", Error: "invalid", Name: "done", TargetID: "1"}) }
+	_, err = synchronousEventWithContext(context.Background(), c, l, w, Event{CallbackID: "3", Name: "order", TargetID: "1"}, "done")
+	assert.EqualError(t, err, "invalid")
+
+	// Test context cancelled
+	mw.fn = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = synchronousEventWithContext(ctx, c, l, w, Event{CallbackID: "4", Name: "order", TargetID: "1"}, "done")
+	assert.EqualError(t, err, context.Canceled.Error())
+	assert.Len(t, mw.w, 2)
+
+	// Test listener is removed when the context is done while waiting
+	ctx, cancel = context.WithCancel(context.Background())
+	mw.fn = cancel
+	_, err = synchronousEventWithContext(ctx, c, l, w, Event{CallbackID: "5", Name: "order", TargetID: "1"}, "done")
+	assert.EqualError(t, err, context.Canceled.Error())
+	d.dispatch(Event{CallbackID: "6", Name: "done", TargetID: "1"})
+	for i := 0; i < 100 && len(d.listeners("1", "done")) > 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Len(t, d.listeners("1", "done"), 0)
+}
+
+func TestSynchronousChunkedEvent(t *testing.T) {
+	// Init
+	var d = newDispatcher()
+	var mw = &mockedWriter{}
+	var w = newWriter(mw)
+	var c = asticontext.NewCanceller()
+	var l = &mockedListenable{d: d, id: "1"}
+
+	// Test chunks are reassembled whatever the order
+	mw.fn = func() {
+		d.dispatch(Event{CallbackID: "1", Count: PtrInt(3), Name: "done", TargetID: "1"})
+		d.dispatch(Event{CallbackID: "1", Data: []byte("c"), Index: PtrInt(2), Name: "done", TargetID: "1"})
+		d.dispatch(Event{CallbackID: "1", Data: []byte("a"), Index: PtrInt(0), Name: "done", TargetID: "1"})
+		d.dispatch(Event{CallbackID: "1", Data: []byte("b"), Index: PtrInt(1), Name: "done", TargetID: "1"})
+	}
+	var b, err = synchronousChunkedEvent(context.Background(), c, l, w, Event{CallbackID: "1", Name: "order", TargetID: "1"}, "done")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), b)
+
+	// Test error
+	mw.fn = func() { d.dispatch(Event{CallbackID: "2", Error: "invalid", Name: "done", TargetID: "1"}) }
+	_, err = synchronousChunkedEvent(context.Background(), c, l, w, Event{CallbackID: "2", Name: "order", TargetID: "1"}, "done")
+	assert.EqualError(t, err, "invalid")
+}
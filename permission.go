@@ -0,0 +1,116 @@
+package astilectron
+
+import (
+	"context"
+	"time"
+
+	"github.com/asticode/go-astilog"
+	"github.com/pkg/errors"
+)
+
+// Permission event names
+const (
+	EventNameSessionCmdPermissionCheckCallback       = "session.cmd.permission.check.callback"
+	EventNameSessionCmdPermissionRequestCallback     = "session.cmd.permission.request.callback"
+	EventNameSessionCmdSetPermissionCheckHandler     = "session.cmd.set.permission.check.handler"
+	EventNameSessionCmdSetPermissionRequestHandler   = "session.cmd.set.permission.request.handler"
+	EventNameSessionEventPermissionCheck             = "session.event.permission.check"
+	EventNameSessionEventPermissionRequest           = "session.event.permission.request"
+	EventNameSessionEventSetPermissionCheckHandler   = "session.event.set.permission.check.handler"
+	EventNameSessionEventSetPermissionRequestHandler = "session.event.set.permission.request.handler"
+)
+
+// Permissions
+const (
+	PermissionClipboardRead = "clipboard-read"
+	PermissionFullscreen    = "fullscreen"
+	PermissionGeolocation   = "geolocation"
+	PermissionMedia         = "media"
+	PermissionMIDI          = "midi"
+	PermissionMIDISysex     = "midiSysex"
+	PermissionNotifications = "notifications"
+	PermissionOpenExternal  = "openExternal"
+	PermissionPointerLock   = "pointerLock"
+	PermissionUnknown       = "unknown"
+)
+
+// DefaultPermissionHandlerTimeout represents the maximum duration of permission handlers, the permission is denied
+// once it is reached
+const DefaultPermissionHandlerTimeout = 10 * time.Second
+
+// PermissionRequest represents a permission request or check
+// MediaTypes is only set for PermissionMedia requests, MediaType for PermissionMedia checks and ExternalURL for
+// PermissionOpenExternal requests
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/session.md#sessetpermissionrequesthandlerhandler
+type PermissionRequest struct {
+	ExternalURL string   `json:"externalURL,omitempty"`
+	IsMainFrame *bool    `json:"isMainFrame,omitempty"`
+	MediaType   string   `json:"mediaType,omitempty"`
+	MediaTypes  []string `json:"mediaTypes,omitempty"`
+	Origin      string   `json:"origin,omitempty"`
+	Permission  string   `json:"permission,omitempty"`
+	WindowID    string   `json:"windowId,omitempty"`
+}
+
+// PermissionHandler decides whether a permission is granted
+type PermissionHandler func(ctx context.Context, r PermissionRequest) (allow bool)
+
+// onPermissionCheck answers permission checks with the permission check handler decision
+func (s *Session) onPermissionCheck(e Event) (deleteListener bool) {
+	s.m.Lock()
+	var fn = s.permissionCheck
+	s.m.Unlock()
+	s.answerPermission(fn, e, EventNameSessionCmdPermissionCheckCallback)
+	return
+}
+
+// onPermissionRequest answers permission requests with the permission request handler decision
+func (s *Session) onPermissionRequest(e Event) (deleteListener bool) {
+	s.m.Lock()
+	var fn = s.permissionRequest
+	s.m.Unlock()
+	s.answerPermission(fn, e, EventNameSessionCmdPermissionRequestCallback)
+	return
+}
+
+// answerPermission executes the handler and sends its decision back to the JS
+// The permission is denied if there's no handler or if the handler doesn't return before the context is done
+func (s *Session) answerPermission(fn PermissionHandler, e Event, eventNameCallback string) {
+	var allow bool
+	if fn != nil && e.PermissionRequest != nil {
+		var r = *e.PermissionRequest
+		allow = timedDecision(DefaultPermissionHandlerTimeout, false, func(ctx context.Context) bool { return fn(ctx, r) })
+	}
+	if err := s.w.write(Event{Allow: PtrBool(allow), CallbackID: e.CallbackID, Name: eventNameCallback, TargetID: s.id}); err != nil {
+		astilog.Error(errors.Wrapf(err, "sending %s failed", eventNameCallback))
+	}
+}
+
+// permissionHandlerTimeout returns the timeout the JS waits for a decision with, nil meaning the JS stops delegating
+// decisions to GO and Electron's default behavior is restored
+func permissionHandlerTimeout(fn PermissionHandler) *int {
+	if fn == nil {
+		return nil
+	}
+	return PtrInt(int(DefaultPermissionHandlerTimeout / time.Millisecond))
+}
+
+// SetPermissionCheckHandler sets the handler deciding whether a page has a permission when it checks it
+// A nil handler restores Electron's default behavior
+func (s *Session) SetPermissionCheckHandler(fn PermissionHandler) (err error) {
+	s.m.Lock()
+	s.permissionCheck = fn
+	s.m.Unlock()
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdSetPermissionCheckHandler, Timeout: permissionHandlerTimeout(fn)}, EventNameSessionEventSetPermissionCheckHandler)
+	return
+}
+
+// SetPermissionRequestHandler sets the handler deciding whether a page is granted a permission it requests
+// A nil handler restores Electron's default behavior
+func (s *Session) SetPermissionRequestHandler(fn PermissionHandler) (err error) {
+	s.m.Lock()
+	s.permissionRequest = fn
+	s.m.Unlock()
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdSetPermissionRequestHandler, Timeout: permissionHandlerTimeout(fn)}, EventNameSessionEventSetPermissionRequestHandler)
+	return
+}
@@ -0,0 +1,89 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_Permissions(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+
+	// No handler
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "10", Name: EventNameSessionEventPermissionRequest, PermissionRequest: &PermissionRequest{Permission: PermissionMedia}, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdPermissionRequestCallback + "\",\"targetID\":\"1\",\"allow\":false,\"callbackId\":\"10\"}\n"}, wrt.w)
+
+	// Set handler
+	wrt.wg = nil
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventSetPermissionRequestHandler, TargetID: s.id})
+	}
+	err := s.SetPermissionRequestHandler(func(ctx context.Context, r PermissionRequest) bool {
+		return r.Permission == PermissionNotifications && r.Origin == "https://github.com" && r.WindowID == "3"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetPermissionRequestHandler + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\",\"timeout\":10000}\n"}, wrt.w)
+
+	// Request
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "11", Name: EventNameSessionEventPermissionRequest, PermissionRequest: &PermissionRequest{Origin: "https://github.com", Permission: PermissionNotifications, WindowID: "3"}, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdPermissionRequestCallback + "\",\"targetID\":\"1\",\"allow\":true,\"callbackId\":\"11\"}\n"}, wrt.w)
+
+	// The check handler is independent
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "12", Name: EventNameSessionEventPermissionCheck, PermissionRequest: &PermissionRequest{Origin: "https://github.com", Permission: PermissionNotifications, WindowID: "3"}, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdPermissionCheckCallback + "\",\"targetID\":\"1\",\"allow\":false,\"callbackId\":\"12\"}\n"}, wrt.w)
+
+	// Set check handler
+	wrt.wg = nil
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "3", Name: EventNameSessionEventSetPermissionCheckHandler, TargetID: s.id})
+	}
+	err = s.SetPermissionCheckHandler(func(ctx context.Context, r PermissionRequest) bool {
+		return r.Permission == PermissionMedia && r.MediaType == "video"
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetPermissionCheckHandler + "\",\"targetID\":\"1\",\"callbackId\":\"3\",\"partition\":\"\",\"timeout\":10000}\n"}, wrt.w)
+
+	// Check
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "13", Name: EventNameSessionEventPermissionCheck, PermissionRequest: &PermissionRequest{MediaType: "video", Origin: "https://github.com", Permission: PermissionMedia}, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdPermissionCheckCallback + "\",\"targetID\":\"1\",\"allow\":true,\"callbackId\":\"13\"}\n"}, wrt.w)
+
+	// A nil handler restores Electron's default behavior
+	wrt.wg = nil
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Name: EventNameSessionEventSetPermissionRequestHandler, TargetID: s.id})
+	}
+	err = s.SetPermissionRequestHandler(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetPermissionRequestHandler + "\",\"targetID\":\"1\",\"callbackId\":\"4\",\"partition\":\"\"}\n"}, wrt.w)
+	s.m.Lock()
+	assert.Nil(t, s.permissionRequest)
+	s.m.Unlock()
+}
@@ -0,0 +1,107 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/pkg/errors"
+)
+
+// Power monitor event names
+const (
+	EventNamePowerMonitorCmdGetSystemIdleState   = "power.monitor.cmd.get.system.idle.state"
+	EventNamePowerMonitorCmdGetSystemIdleTime    = "power.monitor.cmd.get.system.idle.time"
+	EventNamePowerMonitorEventGetSystemIdleState = "power.monitor.event.get.system.idle.state"
+	EventNamePowerMonitorEventGetSystemIdleTime  = "power.monitor.event.get.system.idle.time"
+	EventNamePowerMonitorEventLockScreen         = "power.monitor.event.lock.screen"
+	EventNamePowerMonitorEventOnAC               = "power.monitor.event.on.ac"
+	EventNamePowerMonitorEventOnBattery          = "power.monitor.event.on.battery"
+	EventNamePowerMonitorEventResume             = "power.monitor.event.resume"
+	EventNamePowerMonitorEventShutdown           = "power.monitor.event.shutdown"
+	EventNamePowerMonitorEventSuspend            = "power.monitor.event.suspend"
+	EventNamePowerMonitorEventUnlockScreen       = "power.monitor.event.unlock.screen"
+	EventNamePowerSaveBlockerCmdStart            = "power.save.blocker.cmd.start"
+	EventNamePowerSaveBlockerCmdStop             = "power.save.blocker.cmd.stop"
+	EventNamePowerSaveBlockerEventStarted        = "power.save.blocker.event.started"
+	EventNamePowerSaveBlockerEventStopped        = "power.save.blocker.event.stopped"
+)
+
+// System idle states
+const (
+	SystemIdleStateActive  = "active"
+	SystemIdleStateIdle    = "idle"
+	SystemIdleStateLocked  = "locked"
+	SystemIdleStateUnknown = "unknown"
+)
+
+// Power save blocker types
+const (
+	PowerSaveBlockerTypePreventAppSuspension = "prevent-app-suspension"
+	PowerSaveBlockerTypePreventDisplaySleep  = "prevent-display-sleep"
+)
+
+// PowerMonitor represents a power monitor
+// Listen to its events with On
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/power-monitor.md
+type PowerMonitor struct {
+	*object
+}
+
+func newPowerMonitor(c *asticontext.Canceller, d *dispatcher, i *identifier, wrt *writer) *PowerMonitor {
+	return &PowerMonitor{object: newObject(nil, c, d, i, wrt, targetIDPowerMonitor)}
+}
+
+// GetSystemIdleState returns the system idle state
+// The system is considered idle after idleThreshold seconds without input
+func (p *PowerMonitor) GetSystemIdleState(idleThreshold int) (state string, err error) {
+	if err = p.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(p.ctx, p.c, p, p.w, Event{CallbackID: p.i.new(), IdleThreshold: PtrInt(idleThreshold), Name: EventNamePowerMonitorCmdGetSystemIdleState, TargetID: p.id}, EventNamePowerMonitorEventGetSystemIdleState); err != nil {
+		return
+	}
+	state = e.IdleState
+	return
+}
+
+// GetSystemIdleTime returns the system idle time in seconds
+func (p *PowerMonitor) GetSystemIdleTime() (idleTime int, err error) {
+	if err = p.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(p.ctx, p.c, p, p.w, Event{CallbackID: p.i.new(), Name: EventNamePowerMonitorCmdGetSystemIdleTime, TargetID: p.id}, EventNamePowerMonitorEventGetSystemIdleTime); err != nil {
+		return
+	}
+	if e.IdleTime != nil {
+		idleTime = *e.IdleTime
+	}
+	return
+}
+
+// PreventSleep prevents the system from entering the sleep mode described by the power save blocker type until
+// release is called
+// Only the first call to release stops the power save blocker, the following ones return nil
+func (a *Astilectron) PreventSleep(ctx context.Context, blockerType string) (release func(ctx context.Context) error, err error) {
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{BlockerType: blockerType, CallbackID: a.identifier.new(), Name: EventNamePowerSaveBlockerCmdStart, TargetID: targetIDApp}, EventNamePowerSaveBlockerEventStarted); err != nil {
+		return
+	}
+	if e.ID == nil {
+		err = errors.New("no power save blocker id received")
+		return
+	}
+	var id = *e.ID
+	var o sync.Once
+	release = func(ctx context.Context) (err error) {
+		o.Do(func() {
+			if _, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, Event{CallbackID: a.identifier.new(), ID: PtrInt(id), Name: EventNamePowerSaveBlockerCmdStop, TargetID: targetIDApp}, EventNamePowerSaveBlockerEventStopped); err != nil {
+				err = errors.Wrapf(err, "stopping power save blocker %d failed", id)
+			}
+		})
+		return
+	}
+	return
+}
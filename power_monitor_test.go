@@ -0,0 +1,69 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerMonitor_Actions(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var p = newPowerMonitor(c, d, i, w)
+
+	// Idle state
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "1", IdleState: SystemIdleStateLocked, Name: EventNamePowerMonitorEventGetSystemIdleState, TargetID: targetIDPowerMonitor})
+	}
+	s, err := p.GetSystemIdleState(60)
+	assert.NoError(t, err)
+	assert.Equal(t, SystemIdleStateLocked, s)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNamePowerMonitorCmdGetSystemIdleState + "\",\"targetID\":\"power.monitor\",\"callbackId\":\"1\",\"idleThreshold\":60}\n"}, wrt.w)
+
+	// Idle time
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", IdleTime: PtrInt(30), Name: EventNamePowerMonitorEventGetSystemIdleTime, TargetID: targetIDPowerMonitor})
+	}
+	it, err := p.GetSystemIdleTime()
+	assert.NoError(t, err)
+	assert.Equal(t, 30, it)
+}
+
+func TestAstilectron_PreventSleep(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+
+	// Start
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", ID: PtrInt(4), Name: EventNamePowerSaveBlockerEventStarted, TargetID: targetIDApp})
+	}
+	release, err := a.PreventSleep(context.Background(), PowerSaveBlockerTypePreventDisplaySleep)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNamePowerSaveBlockerCmdStart + "\",\"targetID\":\"app\",\"blockerType\":\"prevent-display-sleep\",\"callbackId\":\"1\"}\n"}, wrt.w)
+
+	// Release
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNamePowerSaveBlockerEventStopped, TargetID: targetIDApp})
+	}
+	var wg sync.WaitGroup
+	for idx := 0; idx < 2; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, release(context.Background()))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNamePowerSaveBlockerCmdStop + "\",\"targetID\":\"app\",\"callbackId\":\"2\",\"id\":4}\n"}, wrt.w)
+}
@@ -0,0 +1,56 @@
+package astilectron
+
+import "encoding/json"
+
+// Print margins types
+const (
+	PrintMarginsTypeDefault = 0
+	PrintMarginsTypeMinimum = 2
+	PrintMarginsTypeNone    = 1
+)
+
+// Print page sizes
+var (
+	PrintPageSizeA3      = &PrintPageSize{Name: "A3"}
+	PrintPageSizeA4      = &PrintPageSize{Name: "A4"}
+	PrintPageSizeA5      = &PrintPageSize{Name: "A5"}
+	PrintPageSizeLegal   = &PrintPageSize{Name: "Legal"}
+	PrintPageSizeLetter  = &PrintPageSize{Name: "Letter"}
+	PrintPageSizeTabloid = &PrintPageSize{Name: "Tabloid"}
+)
+
+// PrintOptions represents print options
+// We must use pointers since GO doesn't handle optional fields whereas NodeJS does. Use PtrBool, PtrInt or PtrStr
+// to fill the struct
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#contentsprintoptions
+type PrintOptions struct {
+	DeviceName      string `json:"deviceName,omitempty"`
+	PrintBackground *bool  `json:"printBackground,omitempty"`
+	Silent          *bool  `json:"silent,omitempty"`
+}
+
+// PrintToPDFOptions represents print to PDF options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#contentsprinttopdfoptions-callback
+type PrintToPDFOptions struct {
+	Landscape          *bool          `json:"landscape,omitempty"`
+	MarginsType        *int           `json:"marginsType,omitempty"`
+	PageSize           *PrintPageSize `json:"pageSize,omitempty"`
+	PrintBackground    *bool          `json:"printBackground,omitempty"`
+	PrintSelectionOnly *bool          `json:"printSelectionOnly,omitempty"`
+}
+
+// PrintPageSize represents a print page size
+// Either a name such as "A4" or a custom size in microns
+type PrintPageSize struct {
+	Height int
+	Name   string
+	Width  int
+}
+
+// MarshalJSON implements the JSONMarshaler interface
+func (s *PrintPageSize) MarshalJSON() ([]byte, error) {
+	if len(s.Name) > 0 {
+		return json.Marshal(s.Name)
+	}
+	return json.Marshal(map[string]int{"height": s.Height, "width": s.Width})
+}
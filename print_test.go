@@ -0,0 +1,17 @@
+package astilectron
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintPageSize_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(PrintToPDFOptions{PageSize: PrintPageSizeA4})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"pageSize\":\"A4\"}", string(b))
+	b, err = json.Marshal(PrintToPDFOptions{PageSize: &PrintPageSize{Height: 1, Width: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"pageSize\":{\"height\":1,\"width\":2}}", string(b))
+}
@@ -0,0 +1,213 @@
+package astilectron
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/asticode/go-astilog"
+	"github.com/pkg/errors"
+)
+
+// Protocol event names
+const (
+	EventNameProtocolCmdRegister     = "protocol.cmd.register"
+	EventNameProtocolCmdResponseData = "protocol.cmd.response.data"
+	EventNameProtocolCmdResponseEnd  = "protocol.cmd.response.end"
+	EventNameProtocolCmdResponseHead = "protocol.cmd.response.head"
+	EventNameProtocolEventRegistered = "protocol.event.registered"
+	EventNameProtocolEventRequest    = "protocol.event.request"
+)
+
+// Electron needs to know privileged schemes before being ready, so they're sent through this environment variable
+// as a comma separated list
+const envPrivilegedSchemes = "ASTILECTRON_PRIVILEGED_SCHEMES"
+
+// Maximum size of the response data sent in one event
+const protocolChunkSize = 64 * 1024
+
+// Vars
+var regexpScheme = regexp.MustCompile("^[a-z][a-z0-9+.-]*$")
+
+// ProtocolRequest represents a request made to a custom protocol
+type ProtocolRequest struct {
+	Body    []byte              `json:"body,omitempty"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Method  string              `json:"method,omitempty"`
+	URL     string              `json:"url,omitempty"`
+}
+
+// ProtocolResponse represents the head of a response to a custom protocol request
+type ProtocolResponse struct {
+	Headers    map[string][]string `json:"headers,omitempty"`
+	StatusCode int                 `json:"statusCode"`
+}
+
+// RegisterProtocol registers a standard and secure scheme whose requests are served by the handler
+// Each request is forwarded to GO and its response is streamed back, so that windows can load URLs such as
+// app://host/index.html without exposing a local HTTP server. It must be called before Start.
+func (a *Astilectron) RegisterProtocol(scheme string, h http.Handler) (err error) {
+	if !regexpScheme.MatchString(scheme) {
+		err = errors.Errorf("scheme %s is invalid", scheme)
+		return
+	}
+	a.m.Lock()
+	defer a.m.Unlock()
+	if a.started {
+		err = errors.Errorf("registering protocol %s failed: protocols must be registered before Start", scheme)
+		return
+	}
+	a.protocols[scheme] = h
+	return
+}
+
+// privilegedSchemes returns the sorted registered schemes
+func (a *Astilectron) privilegedSchemes() (schemes []string) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	for s := range a.protocols {
+		schemes = append(schemes, s)
+	}
+	sort.Strings(schemes)
+	return
+}
+
+// registerProtocols asks Electron to forward the requests of the registered schemes to GO
+func (a *Astilectron) registerProtocols() (err error) {
+	for _, s := range a.privilegedSchemes() {
+		if _, err = synchronousEventWithContext(context.Background(), a.canceller, a, a.writer, Event{CallbackID: a.identifier.new(), Name: EventNameProtocolCmdRegister, Scheme: s, TargetID: targetIDApp}, EventNameProtocolEventRegistered); err != nil {
+			err = errors.Wrapf(err, "registering protocol %s failed", s)
+			return
+		}
+	}
+	return
+}
+
+// onProtocolRequest serves the request with the handler of its scheme
+func (a *Astilectron) onProtocolRequest(e Event) (deleteListener bool) {
+	// Build response writer
+	var rw = &protocolResponseWriter{callbackID: e.CallbackID, header: make(http.Header), w: a.writer}
+	defer rw.end()
+
+	// Build request
+	if e.ProtocolRequest == nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var ctx, cancel = a.canceller.NewContext()
+	defer cancel()
+	r, err := http.NewRequest(e.ProtocolRequest.Method, e.ProtocolRequest.URL, bytes.NewReader(e.ProtocolRequest.Body))
+	if err != nil {
+		astilog.Error(errors.Wrapf(err, "creating request for %s failed", e.ProtocolRequest.URL))
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r = r.WithContext(ctx)
+	for k, vs := range e.ProtocolRequest.Headers {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	r.RequestURI = r.URL.RequestURI()
+
+	// Get handler
+	a.m.Lock()
+	h, ok := a.protocols[r.URL.Scheme]
+	a.m.Unlock()
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Serve
+	defer func() {
+		if v := recover(); v != nil {
+			astilog.Error(errors.Errorf("serving %s panicked: %v", r.URL, v))
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+	}()
+	h.ServeHTTP(rw, r)
+	return
+}
+
+// protocolResponseWriter streams a response to Electron
+// The head is sent on the first write, the body is sent in chunks and the end is sent once the handler returns
+type protocolResponseWriter struct {
+	callbackID  string
+	header      http.Header
+	index       int
+	m           sync.Mutex
+	wroteHeader bool
+	w           *writer
+}
+
+// Header implements the http.ResponseWriter interface
+func (rw *protocolResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+// WriteHeader implements the http.ResponseWriter interface
+func (rw *protocolResponseWriter) WriteHeader(statusCode int) {
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	rw.writeHeader(statusCode)
+}
+
+func (rw *protocolResponseWriter) writeHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	var h = make(map[string][]string)
+	for k, vs := range rw.header {
+		h[k] = append([]string{}, vs...)
+	}
+	if err := rw.w.write(Event{CallbackID: rw.callbackID, Name: EventNameProtocolCmdResponseHead, ProtocolResponse: &ProtocolResponse{Headers: h, StatusCode: statusCode}, TargetID: targetIDApp}); err != nil {
+		astilog.Error(errors.Wrap(err, "sending protocol response head failed"))
+	}
+}
+
+// Write implements the http.ResponseWriter interface
+func (rw *protocolResponseWriter) Write(b []byte) (n int, err error) {
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	if !rw.wroteHeader {
+		if rw.header.Get("Content-Type") == "" {
+			rw.header.Set("Content-Type", http.DetectContentType(b))
+		}
+		rw.writeHeader(http.StatusOK)
+	}
+	for len(b) > 0 {
+		var c = b
+		if len(c) > protocolChunkSize {
+			c = c[:protocolChunkSize]
+		}
+		if err = rw.w.write(Event{CallbackID: rw.callbackID, Data: c, Index: PtrInt(rw.index), Name: EventNameProtocolCmdResponseData, TargetID: targetIDApp}); err != nil {
+			err = errors.Wrap(err, "sending protocol response data failed")
+			return
+		}
+		rw.index++
+		n += len(c)
+		b = b[len(c):]
+	}
+	return
+}
+
+// end sends the end of the response holding the number of chunks sent
+func (rw *protocolResponseWriter) end() {
+	rw.m.Lock()
+	defer rw.m.Unlock()
+	rw.writeHeader(http.StatusOK)
+	if err := rw.w.write(Event{CallbackID: rw.callbackID, Count: PtrInt(rw.index), Name: EventNameProtocolCmdResponseEnd, TargetID: targetIDApp}); err != nil {
+		astilog.Error(errors.Wrap(err, "sending protocol response end failed"))
+	}
+}
+
+// privilegedSchemesEnv returns the environment variable holding the privileged schemes
+func privilegedSchemesEnv(schemes []string) string {
+	return envPrivilegedSchemes + "=" + strings.Join(schemes, ",")
+}
@@ -0,0 +1,81 @@
+package astilectron
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAstilectron_RegisterProtocol(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+
+	// Register
+	assert.Error(t, a.RegisterProtocol("App://", http.NotFoundHandler()))
+	err = a.RegisterProtocol("app", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("panic")
+		}
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Header().Set("X-Method", r.Method)
+		rw.Write([]byte(r.URL.Path + " " + r.Header.Get("X-Test")))
+	}))
+	assert.NoError(t, err)
+	assert.NoError(t, a.RegisterProtocol("another", http.NotFoundHandler()))
+	assert.Equal(t, []string{"another", "app"}, a.privilegedSchemes())
+	assert.Equal(t, "ASTILECTRON_PRIVILEGED_SCHEMES=another,app", privilegedSchemesEnv(a.privilegedSchemes()))
+	a.started = true
+	assert.Error(t, a.RegisterProtocol("late", http.NotFoundHandler()))
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+
+	// Register in Electron
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: strconv.Itoa(len(wrt.w)), Name: EventNameProtocolEventRegistered, TargetID: targetIDApp})
+	}
+	err = a.registerProtocols()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"{\"name\":\"" + EventNameProtocolCmdRegister + "\",\"targetID\":\"app\",\"callbackId\":\"1\",\"scheme\":\"another\"}\n",
+		"{\"name\":\"" + EventNameProtocolCmdRegister + "\",\"targetID\":\"app\",\"callbackId\":\"2\",\"scheme\":\"app\"}\n",
+	}, wrt.w)
+
+	// Request
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(3)
+	a.dispatcher.dispatch(Event{CallbackID: "10", Name: EventNameProtocolEventRequest, ProtocolRequest: &ProtocolRequest{Headers: map[string][]string{"X-Test": {"test"}}, Method: http.MethodGet, URL: "app://host/index.html"}, TargetID: targetIDApp})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{
+		"{\"name\":\"" + EventNameProtocolCmdResponseHead + "\",\"targetID\":\"app\",\"callbackId\":\"10\",\"protocolResponse\":{\"headers\":{\"Content-Type\":[\"text/plain\"],\"X-Method\":[\"GET\"]},\"statusCode\":200}}\n",
+		"{\"name\":\"" + EventNameProtocolCmdResponseData + "\",\"targetID\":\"app\",\"callbackId\":\"10\",\"data\":\"L2luZGV4Lmh0bWwgdGVzdA==\",\"index\":0}\n",
+		"{\"name\":\"" + EventNameProtocolCmdResponseEnd + "\",\"targetID\":\"app\",\"callbackId\":\"10\",\"count\":1}\n",
+	}, wrt.w)
+
+	// Large response
+	var b = strings.Repeat("a", protocolChunkSize+1)
+	var rw = &protocolResponseWriter{callbackID: "11", header: make(http.Header), w: a.writer}
+	wrt.w = []string{}
+	wrt.wg.Add(4)
+	n, err := rw.Write([]byte(b))
+	rw.end()
+	assert.NoError(t, err)
+	assert.Equal(t, len(b), n)
+	assert.Len(t, wrt.w, 4)
+
+	// Panic
+	wrt.w = []string{}
+	wrt.wg.Add(2)
+	a.dispatcher.dispatch(Event{CallbackID: "12", Name: EventNameProtocolEventRequest, ProtocolRequest: &ProtocolRequest{Method: http.MethodGet, URL: "app://host/panic"}, TargetID: targetIDApp})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{
+		"{\"name\":\"" + EventNameProtocolCmdResponseHead + "\",\"targetID\":\"app\",\"callbackId\":\"12\",\"protocolResponse\":{\"statusCode\":500}}\n",
+		"{\"name\":\"" + EventNameProtocolCmdResponseEnd + "\",\"targetID\":\"app\",\"callbackId\":\"12\",\"count\":0}\n",
+	}, wrt.w)
+}
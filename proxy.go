@@ -0,0 +1,80 @@
+package astilectron
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Proxy event names
+const (
+	EventNameSessionCmdCloseAllConnections    = "session.cmd.close.all.connections"
+	EventNameSessionCmdResolveProxy           = "session.cmd.resolve.proxy"
+	EventNameSessionCmdSetProxy               = "session.cmd.set.proxy"
+	EventNameSessionEventClosedAllConnections = "session.event.closed.all.connections"
+	EventNameSessionEventResolvedProxy        = "session.event.resolved.proxy"
+	EventNameSessionEventSetProxy             = "session.event.set.proxy"
+)
+
+// minElectronMajorVersionCloseAllConnections is the first Electron major version providing session.closeAllConnections
+const minElectronMajorVersionCloseAllConnections = 9
+
+// ProxyConfig represents a proxy config
+// PACScript is the URL of a PAC script. PACScriptContent is a PAC script provided by GO which is sent as a data URL,
+// only one of them can be set.
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/session.md#sessetproxyconfig-callback
+type ProxyConfig struct {
+	BypassRules      string `json:"proxyBypassRules,omitempty"`
+	PACScript        string `json:"pacScript,omitempty"`
+	PACScriptContent string `json:"-"`
+	Rules            string `json:"proxyRules,omitempty"`
+}
+
+// SetProxy sets the session's proxy config
+// Open connections are not affected
+func (s *Session) SetProxy(ctx context.Context, c ProxyConfig) (err error) {
+	if err = s.isActionable(); err != nil {
+		return
+	}
+	if c.PACScriptContent != "" {
+		if c.PACScript != "" {
+			err = errors.New("both PACScript and PACScriptContent are set")
+			return
+		}
+		c.PACScript = "data:application/x-ns-proxy-autoconfig;base64," + base64.StdEncoding.EncodeToString([]byte(c.PACScriptContent))
+	}
+	_, err = synchronousEventWithContext(ctx, s.c, s, s.w, Event{CallbackID: s.i.new(), Name: EventNameSessionCmdSetProxy, Partition: PtrStr(s.partition), ProxyConfig: &c, TargetID: s.id}, EventNameSessionEventSetProxy)
+	return
+}
+
+// ResolveProxy returns the proxy used for the URL, e.g. "DIRECT" or "PROXY host:port"
+func (s *Session) ResolveProxy(ctx context.Context, url string) (proxy string, err error) {
+	if err = s.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, s.c, s, s.w, Event{CallbackID: s.i.new(), Name: EventNameSessionCmdResolveProxy, Partition: PtrStr(s.partition), TargetID: s.id, URL: url}, EventNameSessionEventResolvedProxy); err != nil {
+		return
+	}
+	proxy = e.Proxy
+	return
+}
+
+// CloseAllConnections closes the session's open connections so that new ones use the current proxy config
+// Pending requests fail
+// It requires Electron >= 9 and returns an error with the Electron version provisioned by this package
+// https://github.com/electron/electron/blob/v9.0.0/docs/api/session.md#sescloseallconnections
+func (s *Session) CloseAllConnections(ctx context.Context) (err error) {
+	if err = s.isActionable(); err != nil {
+		return
+	}
+	if v, _ := strconv.Atoi(strings.Split(VersionElectron, ".")[0]); v < minElectronMajorVersionCloseAllConnections {
+		err = errors.Errorf("closing all connections requires Electron >= %d, current version is %s", minElectronMajorVersionCloseAllConnections, VersionElectron)
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, s.c, s, s.w, Event{CallbackID: s.i.new(), Name: EventNameSessionCmdCloseAllConnections, Partition: PtrStr(s.partition), TargetID: s.id}, EventNameSessionEventClosedAllConnections)
+	return
+}
@@ -0,0 +1,46 @@
+package astilectron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSession_Proxy(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+
+	// Set proxy
+	wrt.fn = func() { d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventSetProxy, TargetID: s.id}) }
+	err := s.SetProxy(context.Background(), ProxyConfig{PACScript: "http://pac", PACScriptContent: "function FindProxyForURL(url, host) { return \"DIRECT\"; }"})
+	assert.EqualError(t, err, "both PACScript and PACScriptContent are set")
+	err = s.SetProxy(context.Background(), ProxyConfig{PACScriptContent: "function FindProxyForURL(url, host) { return \"DIRECT\"; }"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetProxy + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\",\"proxyConfig\":{\"pacScript\":\"data:application/x-ns-proxy-autoconfig;base64,ZnVuY3Rpb24gRmluZFByb3h5Rm9yVVJMKHVybCwgaG9zdCkgeyByZXR1cm4gIkRJUkVDVCI7IH0=\"}}\n"}, wrt.w)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	wrt.fn = nil
+	err = s.SetProxy(ctx, ProxyConfig{Rules: "direct://"})
+	assert.EqualError(t, err, context.Canceled.Error())
+
+	// Resolve proxy
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Name: EventNameSessionEventResolvedProxy, Proxy: "PROXY host:8080", TargetID: s.id})
+	}
+	p, err := s.ResolveProxy(context.Background(), "https://github.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "PROXY host:8080", p)
+
+	// Close all connections
+	wrt.w = []string{}
+	err = s.CloseAllConnections(context.Background())
+	assert.EqualError(t, err, "closing all connections requires Electron >= 9, current version is "+VersionElectron)
+	assert.Len(t, wrt.w, 0)
+}
@@ -2,35 +2,285 @@ package astilectron
 
 import (
 	"context"
+	"sync"
 
+	"github.com/asticode/go-astilog"
 	"github.com/asticode/go-astitools/context"
+	"github.com/pkg/errors"
 )
 
 // Session event names
 const (
-	EventNameSessionCmdClearCache     = "session.cmd.clear.cache"
-	EventNameSessionEventClearedCache = "session.event.cleared.cache"
-	EventNameSessionEventWillDownload = "session.event.will.download"
+	EventNameSessionCmdClearAuthCache             = "session.cmd.clear.auth.cache"
+	EventNameSessionCmdClearCache                 = "session.cmd.clear.cache"
+	EventNameSessionCmdClearHostResolverCache     = "session.cmd.clear.host.resolver.cache"
+	EventNameSessionCmdClearStorageData           = "session.cmd.clear.storage.data"
+	EventNameSessionCmdCreate                     = "session.cmd.create"
+	EventNameSessionCmdFlushStorageData           = "session.cmd.flush.storage.data"
+	EventNameSessionCmdGetCacheSize               = "session.cmd.get.cache.size"
+	EventNameSessionCmdWillDownloadCallback       = "session.cmd.will.download.callback"
+	EventNameSessionEventClearedAuthCache         = "session.event.cleared.auth.cache"
+	EventNameSessionEventClearedCache             = "session.event.cleared.cache"
+	EventNameSessionEventClearedHostResolverCache = "session.event.cleared.host.resolver.cache"
+	EventNameSessionEventClearedStorageData       = "session.event.cleared.storage.data"
+	EventNameSessionEventCreated                  = "session.event.created"
+	EventNameSessionEventFlushedStorageData       = "session.event.flushed.storage.data"
+	EventNameSessionEventGetCacheSize             = "session.event.get.cache.size"
+	EventNameSessionEventWillDownload             = "session.event.will.download"
 )
 
+// Session storages
+const (
+	StorageAppCache       = "appcache"
+	StorageCacheStorage   = "cachestorage"
+	StorageCookies        = "cookies"
+	StorageFileSystem     = "filesystem"
+	StorageIndexDB        = "indexdb"
+	StorageLocalStorage   = "localstorage"
+	StorageServiceWorkers = "serviceworkers"
+	StorageShaderCache    = "shadercache"
+	StorageWebSQL         = "websql"
+)
+
+// Session storage quotas
+const (
+	StorageQuotaPersistent = "persistent"
+	StorageQuotaSyncable   = "syncable"
+	StorageQuotaTemporary  = "temporary"
+)
+
+// StorageDataOptions represents storage data options
+// Empty fields match everything
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/session.md#sesclearstoragedataoptions-callback
+type StorageDataOptions struct {
+	Origin   string   `json:"origin,omitempty"`
+	Quotas   []string `json:"quotas,omitempty"`
+	Storages []string `json:"storages,omitempty"`
+}
+
 // Session Manages browser sessions, cookies, cache, proxy settings, etc.
+// Session commands hold the partition so that Electron can reach the session before any window uses it
 // TODO Add missing session methods
 // TODO Add missing session events
 // https://github.com/electron/electron/blob/v1.8.1/docs/api/session.md
 type Session struct {
 	*object
+	certificateVerifyProc func(ctx context.Context, r CertificateVerifyRequest) (result int)
+	cookies               *Cookies
+	downloadItems         map[string]*DownloadItem // Indexed by download item ID
+	m                     sync.Mutex               // Locks certificateVerifyProc, downloadItems, onDownload, permissionCheck and permissionRequest
+	onDownload            func(ctx context.Context, d *DownloadItem) DownloadDecision
+	partition             string
+	permissionCheck       PermissionHandler
+	permissionRequest     PermissionHandler
+	webRequest            *WebRequest
+}
+
+// sessionEntry represents a session being created or created for a partition
+type sessionEntry struct {
+	done chan struct{}
+	err  error
+	s    *Session
 }
 
 // newSession creates a new session
-func newSession(parentCtx context.Context, c *asticontext.Canceller, d *dispatcher, i *identifier, w *writer) *Session {
-	return &Session{object: newObject(parentCtx, c, d, i, w, i.new())}
+func newSession(parentCtx context.Context, c *asticontext.Canceller, d *dispatcher, i *identifier, w *writer, partition string) (s *Session) {
+	var o = newObject(parentCtx, c, d, i, w, i.new())
+	s = &Session{object: o, cookies: &Cookies{object: o, partition: partition}, downloadItems: make(map[string]*DownloadItem), partition: partition, webRequest: newWebRequest(o, partition)}
+	s.On(EventNameSessionEventCertificateVerify, s.onCertificateVerify)
+	s.On(EventNameSessionEventPermissionCheck, s.onPermissionCheck)
+	s.On(EventNameSessionEventPermissionRequest, s.onPermissionRequest)
+	s.On(EventNameDownloadItemEventDone, s.onDownloadItemEvent)
+	s.On(EventNameDownloadItemEventUpdated, s.onDownloadItemEvent)
+	s.On(EventNameSessionEventWillDownload, s.onWillDownload)
+	return
+}
+
+// onWillDownload creates the download item and sends the OnDownload handler decision back to the JS
+// Electron waits for the decision before the download proceeds, therefore the download item exists before any of its
+// events is received
+func (s *Session) onWillDownload(e Event) (deleteListener bool) {
+	// Create the download item
+	var info DownloadItemInfo
+	if e.DownloadItem != nil {
+		info = *e.DownloadItem
+	}
+	var di = newDownloadItem(s.ctx, s.c, s.d, s.i, s.w, e.DownloadItemID, info)
+
+	// Get decision
+	s.m.Lock()
+	var fn = s.onDownload
+	s.m.Unlock()
+	var dd DownloadDecision
+	if fn != nil {
+		var ctx, cancel = context.WithTimeout(s.ctx, DefaultDownloadHandlerTimeout)
+		var ch = make(chan DownloadDecision, 1)
+		go func() { ch <- fn(ctx, di) }()
+		select {
+		case dd = <-ch:
+		case <-ctx.Done():
+			astilog.Debugf("No download decision has been made in the past %s, cancelling the download", DefaultDownloadHandlerTimeout)
+			dd = DownloadDecision{Cancel: true}
+		}
+		cancel()
+	}
+
+	// Keep track of accepted downloads
+	if dd.Cancel {
+		di.cancel()
+	} else {
+		s.m.Lock()
+		s.downloadItems[di.id] = di
+		s.m.Unlock()
+	}
+
+	// Send message back
+	if err := s.w.write(Event{Allow: PtrBool(!dd.Cancel), CallbackID: e.CallbackID, DownloadItemID: di.id, FilePath: dd.SavePath, Name: EventNameSessionCmdWillDownloadCallback, TargetID: s.id}); err != nil {
+		astilog.Error(errors.Wrap(err, "sending will download callback failed"))
+	}
+	return
+}
+
+// onDownloadItemEvent forwards the download item events Electron sends to the session to the download item
+// Download items are forgotten once they're done
+func (s *Session) onDownloadItemEvent(e Event) (deleteListener bool) {
+	// Get the download item
+	s.m.Lock()
+	di, ok := s.downloadItems[e.DownloadItemID]
+	if ok && e.Name == EventNameDownloadItemEventDone {
+		delete(s.downloadItems, e.DownloadItemID)
+	}
+	s.m.Unlock()
+	if !ok {
+		return
+	}
+
+	// Update the download item
+	if !di.update(e) {
+		return
+	}
+	if e.Name == EventNameDownloadItemEventDone {
+		di.cancel()
+	}
+	s.d.dispatch(Event{DownloadItem: e.DownloadItem, DownloadItemID: di.id, Name: e.Name, TargetID: di.id})
+	return
+}
+
+// Session returns the session shared by every window and call using the partition, creating it if needed
+// Partitions starting with "persist:" are persisted on disk, others are kept in memory. An empty partition is the
+// default session.
+// Use WindowOptions.Session to make a window use it.
+func (a *Astilectron) Session(ctx context.Context, partition string) (s *Session, err error) {
+	// Only the first call creates the session, the others wait for it
+	a.ms.Lock()
+	e, ok := a.sessions[partition]
+	if !ok {
+		e = &sessionEntry{done: make(chan struct{})}
+		a.sessions[partition] = e
+	}
+	a.ms.Unlock()
+	if ok {
+		select {
+		case <-e.done:
+			return e.s, e.err
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+	}
+	defer close(e.done)
+
+	// Create
+	e.s = newSession(nil, a.canceller, a.dispatcher, a.identifier, a.writer, partition)
+	if _, e.err = synchronousEventWithContext(ctx, a.canceller, e.s, a.writer, Event{CallbackID: a.identifier.new(), Name: EventNameSessionCmdCreate, Partition: PtrStr(partition), TargetID: e.s.id}, EventNameSessionEventCreated); e.err != nil {
+		e.err = errors.Wrapf(e.err, "creating session for partition %s failed", partition)
+		e.s = nil
+
+		// Next calls try again
+		a.ms.Lock()
+		delete(a.sessions, partition)
+		a.ms.Unlock()
+	}
+	return e.s, e.err
+}
+
+// Partition returns the session's partition
+// Sessions created along with a window use the partition of the window's web preferences
+func (s *Session) Partition() string {
+	return s.partition
+}
+
+// sendEvent sends a session event and waits for its done event
+func (s *Session) sendEvent(i Event, eventNameDone string) (o Event, err error) {
+	if err = s.isActionable(); err != nil {
+		return
+	}
+	i.CallbackID = s.i.new()
+	i.Partition = PtrStr(s.partition)
+	i.TargetID = s.id
+	o, err = synchronousEventWithContext(s.ctx, s.c, s, s.w, i, eventNameDone)
+	return
+}
+
+// ClearAuthCache clears the Session's HTTP authentication cache
+func (s *Session) ClearAuthCache() (err error) {
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdClearAuthCache}, EventNameSessionEventClearedAuthCache)
+	return
 }
 
 // ClearCache clears the Session's HTTP cache
 func (s *Session) ClearCache() (err error) {
-	if err = s.isActionable(); err != nil {
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdClearCache}, EventNameSessionEventClearedCache)
+	return
+}
+
+// ClearHostResolverCache clears the Session's host resolver cache
+func (s *Session) ClearHostResolverCache() (err error) {
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdClearHostResolverCache}, EventNameSessionEventClearedHostResolverCache)
+	return
+}
+
+// ClearStorageData clears the Session's storage data
+// An empty origin clears all origins, empty storages and quotas clear all storages and quotas
+func (s *Session) ClearStorageData(origin string, storages, quotas []string) (err error) {
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdClearStorageData, StorageData: &StorageDataOptions{Origin: origin, Quotas: quotas, Storages: storages}}, EventNameSessionEventClearedStorageData)
+	return
+}
+
+// FlushStorageData writes any unwritten DOM storage data to disk
+func (s *Session) FlushStorageData() (err error) {
+	_, err = s.sendEvent(Event{Name: EventNameSessionCmdFlushStorageData}, EventNameSessionEventFlushedStorageData)
+	return
+}
+
+// GetCacheSize returns the Session's current cache size in bytes
+func (s *Session) GetCacheSize() (size int64, err error) {
+	var e Event
+	if e, err = s.sendEvent(Event{Name: EventNameSessionCmdGetCacheSize}, EventNameSessionEventGetCacheSize); err != nil {
 		return
 	}
-	_, err = synchronousEvent(s.c, s, s.w, Event{Name: EventNameSessionCmdClearCache, TargetID: s.id}, EventNameSessionEventClearedCache)
+	if e.CacheSize != nil {
+		size = *e.CacheSize
+	}
 	return
 }
+
+// Cookies returns the session's cookies
+func (s *Session) Cookies() *Cookies {
+	return s.cookies
+}
+
+// WebRequest returns the session's web request
+func (s *Session) WebRequest() *WebRequest {
+	return s.webRequest
+}
+
+// OnDownload sets the handler deciding whether a download is accepted, based for instance on its MIME type or URL,
+// and where it is saved
+// The download is cancelled if the handler doesn't return within DefaultDownloadHandlerTimeout. Accepted downloads can
+// then be followed and controlled through the download item.
+func (s *Session) OnDownload(fn func(ctx context.Context, d *DownloadItem) DownloadDecision) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.onDownload = fn
+}
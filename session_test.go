@@ -2,9 +2,12 @@ package astilectron
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSession_Actions(t *testing.T) {
@@ -14,8 +17,223 @@ func TestSession_Actions(t *testing.T) {
 	var i = newIdentifier()
 	var wrt = &mockedWriter{}
 	var w = newWriter(wrt)
-	var s = newSession(context.Background(), c, d, i, w)
+	var s = newSession(context.Background(), c, d, i, w, "")
 
 	// Actions
-	testObjectAction(t, func() error { return s.ClearCache() }, s.object, wrt, "{\"name\":\"session.cmd.clear.cache\",\"targetID\":\"1\"}\n", EventNameSessionEventClearedCache)
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventClearedCache, TargetID: s.id})
+	}
+	err := s.ClearCache()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdClearCache + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\"}\n"}, wrt.w)
+}
+
+func TestSession_Cookies(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+	var cs = s.Cookies()
+
+	// Set
+	wrt.fn = func() { d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventSetCookie, TargetID: s.id}) }
+	err := cs.Set(Cookie{HTTPOnly: PtrBool(true), Name: "name", URL: "https://github.com", Value: "value"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdSetCookie + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"cookie\":{\"httpOnly\":true,\"name\":\"name\",\"url\":\"https://github.com\",\"value\":\"value\"},\"partition\":\"\"}\n"}, wrt.w)
+
+	// Get
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "3", Cookies: []Cookie{{Domain: "github.com", Name: "name", Value: "value"}}, Name: EventNameSessionEventGetCookies, TargetID: s.id})
+	}
+	r, err := cs.Get(CookieFilter{Domain: "github.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Cookie{{Domain: "github.com", Name: "name", Value: "value"}}, r)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdGetCookies + "\",\"targetID\":\"1\",\"callbackId\":\"3\",\"cookieFilter\":{\"domain\":\"github.com\"},\"partition\":\"\"}\n"}, wrt.w)
+
+	// Remove
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Error: "invalid url", Name: EventNameSessionEventRemovedCookie, TargetID: s.id})
+	}
+	err = cs.Remove("invalid", "name")
+	assert.EqualError(t, err, "invalid url")
+
+	// Changed
+	type change struct {
+		c       Cookie
+		cause   string
+		removed bool
+	}
+	var ch = make(chan change, 1)
+	s.OnCookieChanged(func(c Cookie, cause string, removed bool) { ch <- change{c: c, cause: cause, removed: removed} })
+	d.dispatch(Event{Cause: CookieChangedCauseExplicit, Cookie: &Cookie{Name: "name"}, Name: EventNameSessionEventCookieChanged, Removed: PtrBool(true), TargetID: s.id})
+	assert.Equal(t, change{c: Cookie{Name: "name"}, cause: CookieChangedCauseExplicit, removed: true}, <-ch)
+}
+
+func TestSession_OnDownload(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{wg: &sync.WaitGroup{}}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+
+	// No handler
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "10", DownloadItem: &DownloadItemInfo{MimeType: "text/plain"}, DownloadItemID: "download.1", Name: EventNameSessionEventWillDownload, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWillDownloadCallback + "\",\"targetID\":\"1\",\"allow\":true,\"callbackId\":\"10\",\"downloadItemId\":\"download.1\"}\n"}, wrt.w)
+
+	// Handler
+	var items = make(chan *DownloadItem, 1)
+	var done = make(chan DownloadItemInfo, 1)
+	s.OnDownload(func(ctx context.Context, di *DownloadItem) DownloadDecision {
+		if di.Info().MimeType != "application/pdf" {
+			return DownloadDecision{Cancel: true}
+		}
+		di.On(EventNameDownloadItemEventDone, func(e Event) (deleteListener bool) {
+			done <- *e.DownloadItem
+			return
+		})
+		items <- di
+		return DownloadDecision{SavePath: "/path/to/file.pdf"}
+	})
+
+	// Rejected
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "11", DownloadItem: &DownloadItemInfo{MimeType: "text/plain"}, DownloadItemID: "download.2", Name: EventNameSessionEventWillDownload, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWillDownloadCallback + "\",\"targetID\":\"1\",\"allow\":false,\"callbackId\":\"11\",\"downloadItemId\":\"download.2\"}\n"}, wrt.w)
+
+	// Accepted
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "12", DownloadItem: &DownloadItemInfo{MimeType: "application/pdf"}, DownloadItemID: "download.3", Name: EventNameSessionEventWillDownload, TargetID: s.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWillDownloadCallback + "\",\"targetID\":\"1\",\"allow\":true,\"callbackId\":\"12\",\"downloadItemId\":\"download.3\",\"filePath\":\"/path/to/file.pdf\"}\n"}, wrt.w)
+	var di = <-items
+	assert.Equal(t, "download.3", di.id)
+	s.m.Lock()
+	assert.Len(t, s.downloadItems, 2)
+	s.m.Unlock()
+
+	// Done download items are forgotten
+	d.dispatch(Event{DownloadItem: &DownloadItemInfo{ReceivedBytes: 20, State: DownloadItemStateCompleted}, DownloadItemID: "download.3", Name: EventNameDownloadItemEventDone, TargetID: s.id})
+	assert.Equal(t, DownloadItemInfo{ReceivedBytes: 20, State: DownloadItemStateCompleted}, <-done)
+	<-di.ctx.Done()
+	assert.Equal(t, DownloadItemStateCompleted, di.Info().State)
+	assert.EqualError(t, di.Cancel(), ErrObjectDestroyed.Error())
+	s.m.Lock()
+	_, ok := s.downloadItems["download.3"]
+	s.m.Unlock()
+	assert.False(t, ok)
+}
+
+func TestAstilectron_Session(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+
+	// Create
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventCreated, TargetID: "1"})
+	}
+	s, err := a.Session(context.Background(), "persist:shared")
+	assert.NoError(t, err)
+	assert.Equal(t, "persist:shared", s.Partition())
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdCreate + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"persist:shared\"}\n"}, wrt.w)
+
+	// Shared
+	wrt.fn = nil
+	wrt.w = []string{}
+	s2, err := a.Session(context.Background(), "persist:shared")
+	assert.NoError(t, err)
+	assert.True(t, s == s2)
+	assert.Len(t, wrt.w, 0)
+
+	// Windows
+	w1, err := a.NewWindow("http://test.com", &WindowOptions{Session: s})
+	assert.NoError(t, err)
+	w2, err := a.NewWindow("http://test.com", &WindowOptions{Session: s})
+	assert.NoError(t, err)
+	assert.True(t, w1.Session == w2.Session)
+	assert.Equal(t, "persist:shared", *w1.o.WebPreferences.Partition)
+
+	// Error
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "6", Error: "invalid", Name: EventNameSessionEventCreated, TargetID: "5"})
+	}
+	s, err = a.Session(context.Background(), "invalid")
+	assert.Error(t, err)
+	assert.Nil(t, s)
+
+	// Concurrent calls create the session once
+	wrt.w = []string{}
+	var release = make(chan bool)
+	wrt.fn = func() {
+		<-release
+		a.dispatcher.dispatch(Event{CallbackID: "8", Name: EventNameSessionEventCreated, TargetID: "7"})
+	}
+	var ss = make(chan *Session, 2)
+	for idx := 0; idx < 2; idx++ {
+		go func() {
+			s, err := a.Session(context.Background(), "concurrent")
+			assert.NoError(t, err)
+			ss <- s
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	s, s2 = <-ss, <-ss
+	assert.NotNil(t, s)
+	assert.True(t, s == s2)
+	assert.Len(t, wrt.w, 1)
+}
+
+func TestSession_Storage(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+
+	// Clear storage data
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventClearedStorageData, TargetID: s.id})
+	}
+	err := s.ClearStorageData("https://github.com", []string{StorageCookies, StorageLocalStorage}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdClearStorageData + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\",\"storageData\":{\"origin\":\"https://github.com\",\"storages\":[\"cookies\",\"localstorage\"]}}\n"}, wrt.w)
+
+	// Get cache size
+	wrt.fn = func() {
+		d.dispatch(Event{CacheSize: PtrInt64(1024), CallbackID: "3", Name: EventNameSessionEventGetCacheSize, TargetID: s.id})
+	}
+	size, err := s.GetCacheSize()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), size)
+
+	// Other actions
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Name: EventNameSessionEventClearedAuthCache, TargetID: s.id})
+	}
+	assert.NoError(t, s.ClearAuthCache())
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "5", Name: EventNameSessionEventClearedHostResolverCache, TargetID: s.id})
+	}
+	assert.NoError(t, s.ClearHostResolverCache())
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "6", Name: EventNameSessionEventFlushedStorageData, TargetID: s.id})
+	}
+	assert.NoError(t, s.FlushStorageData())
 }
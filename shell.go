@@ -0,0 +1,80 @@
+package astilectron
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Shell event names
+const (
+	EventNameShellCmdBeep                = "shell.cmd.beep"
+	EventNameShellCmdMoveItemToTrash     = "shell.cmd.move.item.to.trash"
+	EventNameShellCmdOpenExternal        = "shell.cmd.open.external"
+	EventNameShellCmdOpenPath            = "shell.cmd.open.path"
+	EventNameShellCmdShowItemInFolder    = "shell.cmd.show.item.in.folder"
+	EventNameShellEventBeeped            = "shell.event.beeped"
+	EventNameShellEventItemMovedToTrash  = "shell.event.item.moved.to.trash"
+	EventNameShellEventOpenedExternal    = "shell.event.opened.external"
+	EventNameShellEventOpenedPath        = "shell.event.opened.path"
+	EventNameShellEventShownItemInFolder = "shell.event.shown.item.in.folder"
+)
+
+// DefaultOpenExternalSchemes represents the URL schemes OpenExternal allows by default
+var DefaultOpenExternalSchemes = []string{"http", "https", "mailto"}
+
+// shellEvent sends a shell event and waits for its done event
+func (a *Astilectron) shellEvent(ctx context.Context, i Event, eventNameDone string) (err error) {
+	i.CallbackID = a.identifier.new()
+	i.TargetID = targetIDApp
+	_, err = synchronousEventWithContext(ctx, a.canceller, a, a.writer, i, eventNameDone)
+	return
+}
+
+// Beep plays the beep sound
+func (a *Astilectron) Beep(ctx context.Context) error {
+	return a.shellEvent(ctx, Event{Name: EventNameShellCmdBeep}, EventNameShellEventBeeped)
+}
+
+// MoveItemToTrash moves the file or directory to the trash
+func (a *Astilectron) MoveItemToTrash(ctx context.Context, path string) error {
+	return a.shellEvent(ctx, Event{FilePath: path, Name: EventNameShellCmdMoveItemToTrash}, EventNameShellEventItemMovedToTrash)
+}
+
+// OpenExternal opens the URL in the desktop's default manner
+// Only URLs whose scheme is in Options.OpenExternalSchemes are opened
+func (a *Astilectron) OpenExternal(ctx context.Context, rawURL string) (err error) {
+	var u *url.URL
+	if u, err = url.Parse(rawURL); err != nil {
+		err = errors.Wrapf(err, "parsing url %s failed", rawURL)
+		return
+	}
+	var schemes = a.options.OpenExternalSchemes
+	if schemes == nil {
+		schemes = DefaultOpenExternalSchemes
+	}
+	var allowed bool
+	for _, s := range schemes {
+		if strings.EqualFold(s, u.Scheme) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		err = errors.Errorf("scheme %s of url %s is not allowed", u.Scheme, rawURL)
+		return
+	}
+	return a.shellEvent(ctx, Event{Name: EventNameShellCmdOpenExternal, URL: rawURL}, EventNameShellEventOpenedExternal)
+}
+
+// OpenPath opens the file or directory in the desktop's default manner
+func (a *Astilectron) OpenPath(ctx context.Context, path string) error {
+	return a.shellEvent(ctx, Event{FilePath: path, Name: EventNameShellCmdOpenPath}, EventNameShellEventOpenedPath)
+}
+
+// ShowItemInFolder shows the file or directory in a file manager and selects it if possible
+func (a *Astilectron) ShowItemInFolder(ctx context.Context, path string) error {
+	return a.shellEvent(ctx, Event{FilePath: path, Name: EventNameShellCmdShowItemInFolder}, EventNameShellEventShownItemInFolder)
+}
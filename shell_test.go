@@ -0,0 +1,51 @@
+package astilectron
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAstilectron_Shell(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+
+	// Open external
+	err = a.OpenExternal(context.Background(), "file:///etc/passwd")
+	assert.Error(t, err)
+	assert.Len(t, wrt.w, 0)
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameShellEventOpenedExternal, TargetID: targetIDApp})
+	}
+	err = a.OpenExternal(context.Background(), "HTTPS://github.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameShellCmdOpenExternal + "\",\"targetID\":\"app\",\"callbackId\":\"1\",\"url\":\"HTTPS://github.com\"}\n"}, wrt.w)
+	a.options.OpenExternalSchemes = []string{"file"}
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNameShellEventOpenedExternal, TargetID: targetIDApp})
+	}
+	err = a.OpenExternal(context.Background(), "file:///tmp")
+	assert.NoError(t, err)
+	err = a.OpenExternal(context.Background(), "http://github.com")
+	assert.Error(t, err)
+	assert.Len(t, wrt.w, 1)
+
+	// Paths
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "3", Error: "not found", Name: EventNameShellEventItemMovedToTrash, TargetID: targetIDApp})
+	}
+	err = a.MoveItemToTrash(context.Background(), "/path/to/file")
+	assert.EqualError(t, err, "not found")
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameShellCmdMoveItemToTrash + "\",\"targetID\":\"app\",\"callbackId\":\"3\",\"filePath\":\"/path/to/file\"}\n"}, wrt.w)
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "4", Name: EventNameShellEventShownItemInFolder, TargetID: targetIDApp})
+	}
+	err = a.ShowItemInFolder(context.Background(), "/path/to/file")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,245 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astilog"
+	"github.com/pkg/errors"
+)
+
+// Web request event names
+const (
+	EventNameSessionCmdWebRequestCallback                      = "session.cmd.web.request.callback"
+	EventNameSessionCmdWebRequestOnBeforeRequest               = "session.cmd.web.request.on.before.request"
+	EventNameSessionCmdWebRequestOnBeforeSendHeaders           = "session.cmd.web.request.on.before.send.headers"
+	EventNameSessionCmdWebRequestOnCompleted                   = "session.cmd.web.request.on.completed"
+	EventNameSessionCmdWebRequestOnErrorOccurred               = "session.cmd.web.request.on.error.occurred"
+	EventNameSessionCmdWebRequestOnHeadersReceived             = "session.cmd.web.request.on.headers.received"
+	EventNameSessionEventWebRequestBeforeRequest               = "session.event.web.request.before.request"
+	EventNameSessionEventWebRequestBeforeRequestHandlerSet     = "session.event.web.request.before.request.handler.set"
+	EventNameSessionEventWebRequestBeforeSendHeaders           = "session.event.web.request.before.send.headers"
+	EventNameSessionEventWebRequestBeforeSendHeadersHandlerSet = "session.event.web.request.before.send.headers.handler.set"
+	EventNameSessionEventWebRequestCompleted                   = "session.event.web.request.completed"
+	EventNameSessionEventWebRequestCompletedListenerSet        = "session.event.web.request.completed.listener.set"
+	EventNameSessionEventWebRequestErrorOccurred               = "session.event.web.request.error.occurred"
+	EventNameSessionEventWebRequestErrorOccurredListenerSet    = "session.event.web.request.error.occurred.listener.set"
+	EventNameSessionEventWebRequestHeadersReceived             = "session.event.web.request.headers.received"
+	EventNameSessionEventWebRequestHeadersReceivedHandlerSet   = "session.event.web.request.headers.received.handler.set"
+)
+
+// Web request timeout policies
+const (
+	// The request proceeds untouched when GO doesn't answer in time
+	WebRequestTimeoutPolicyFailOpen = "fail-open"
+	// The request is cancelled when GO doesn't answer in time
+	WebRequestTimeoutPolicyFailClosed = "fail-closed"
+)
+
+// Default web request timeout
+const DefaultWebRequestTimeout = 5 * time.Second
+
+// WebRequestDetails represents the details of a web request
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-request.md
+type WebRequestDetails struct {
+	Error           string              `json:"error,omitempty"`
+	FromCache       *bool               `json:"fromCache,omitempty"`
+	ID              int                 `json:"id"`
+	IP              string              `json:"ip,omitempty"`
+	Method          string              `json:"method,omitempty"`
+	Referrer        string              `json:"referrer,omitempty"`
+	RequestHeaders  map[string]string   `json:"requestHeaders,omitempty"`
+	ResourceType    string              `json:"resourceType,omitempty"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	StatusCode      *int                `json:"statusCode,omitempty"`
+	StatusLine      string              `json:"statusLine,omitempty"`
+	Timestamp       float64             `json:"timestamp,omitempty"`
+	URL             string              `json:"url,omitempty"`
+	WindowID        string              `json:"windowId,omitempty"`
+}
+
+// WebRequestFilter represents a web request filter
+// Requests whose url doesn't match any of the URL patterns are not sent to GO. An empty filter matches all requests.
+type WebRequestFilter struct {
+	URLs []string `json:"urls,omitempty"`
+}
+
+// WebRequestResponse represents the decision of a web request handler
+// RequestHeaders is only used by OnBeforeSendHeaders handlers, ResponseHeaders and StatusLine by OnHeadersReceived
+// handlers and RedirectURL by OnBeforeRequest and OnHeadersReceived handlers
+type WebRequestResponse struct {
+	Cancel          bool                `json:"cancel,omitempty"`
+	RedirectURL     string              `json:"redirectURL,omitempty"`
+	RequestHeaders  map[string]string   `json:"requestHeaders,omitempty"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	StatusLine      string              `json:"statusLine,omitempty"`
+}
+
+// WebRequestHandler decides what happens to a web request
+// The context is done once the JS stops waiting for the decision and applies the timeout policy instead
+type WebRequestHandler func(ctx context.Context, d WebRequestDetails) WebRequestResponse
+
+// WebRequestListener is notified of a web request
+type WebRequestListener func(d WebRequestDetails)
+
+// webRequestHandler represents a handler and the timeout it was registered with
+type webRequestHandler struct {
+	h       WebRequestHandler
+	timeout time.Duration
+}
+
+// WebRequest intercepts and modifies the web requests of a session
+// Each event accepts only one handler, registering a new one replaces the previous one and registering a nil one
+// removes it
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-request.md
+type WebRequest struct {
+	*object
+	handlers      map[string]webRequestHandler  // Indexed by event name
+	listeners     map[string]WebRequestListener // Indexed by event name
+	m             sync.Mutex                    // Locks handlers, listeners, timeout and timeoutPolicy
+	partition     string
+	timeout       time.Duration
+	timeoutPolicy string
+}
+
+// newWebRequest creates a new web request
+func newWebRequest(o *object, partition string) (r *WebRequest) {
+	r = &WebRequest{
+		handlers:      make(map[string]webRequestHandler),
+		listeners:     make(map[string]WebRequestListener),
+		object:        o,
+		partition:     partition,
+		timeout:       DefaultWebRequestTimeout,
+		timeoutPolicy: WebRequestTimeoutPolicyFailOpen,
+	}
+	r.On(EventNameSessionEventWebRequestBeforeRequest, r.onHandlerEvent)
+	r.On(EventNameSessionEventWebRequestBeforeSendHeaders, r.onHandlerEvent)
+	r.On(EventNameSessionEventWebRequestCompleted, r.onListenerEvent)
+	r.On(EventNameSessionEventWebRequestErrorOccurred, r.onListenerEvent)
+	r.On(EventNameSessionEventWebRequestHeadersReceived, r.onHandlerEvent)
+	return
+}
+
+// onHandlerEvent executes the handler of the event and sends its decision back to the JS
+func (r *WebRequest) onHandlerEvent(e Event) (deleteListener bool) {
+	r.m.Lock()
+	h, ok := r.handlers[e.Name]
+	r.m.Unlock()
+	var resp WebRequestResponse
+	if ok && e.WebRequestDetails != nil {
+		var ctx, cancel = context.WithTimeout(r.ctx, h.timeout)
+		resp = h.h(ctx, *e.WebRequestDetails)
+		cancel()
+	}
+	if err := r.w.write(Event{CallbackID: e.CallbackID, Name: EventNameSessionCmdWebRequestCallback, TargetID: r.id, WebRequestResponse: &resp}); err != nil {
+		astilog.Error(errors.Wrap(err, "sending web request callback failed"))
+	}
+	return
+}
+
+// onListenerEvent executes the listener of the event
+func (r *WebRequest) onListenerEvent(e Event) (deleteListener bool) {
+	r.m.Lock()
+	l, ok := r.listeners[e.Name]
+	r.m.Unlock()
+	if ok && e.WebRequestDetails != nil {
+		l(*e.WebRequestDetails)
+	}
+	return
+}
+
+// SetTimeoutPolicy sets how long the JS waits for the decision of a handler and what it does when the decision
+// doesn't come in time
+// It defaults to DefaultWebRequestTimeout and WebRequestTimeoutPolicyFailOpen and only applies to handlers registered
+// afterwards
+func (r *WebRequest) SetTimeoutPolicy(timeout time.Duration, policy string) (err error) {
+	if timeout <= 0 {
+		err = errors.Errorf("timeout %s is invalid", timeout)
+		return
+	}
+	if policy != WebRequestTimeoutPolicyFailClosed && policy != WebRequestTimeoutPolicyFailOpen {
+		err = errors.Errorf("timeout policy %s is invalid", policy)
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.timeout = timeout
+	r.timeoutPolicy = policy
+	return
+}
+
+// register registers the filter on the JS side, a nil filter removing the JS listener
+// The handler or listener is stored beforehand so that no event is missed once the JS starts intercepting
+func (r *WebRequest) register(f *WebRequestFilter, eventNameCmd, eventNameDone string) (err error) {
+	if err = r.isActionable(); err != nil {
+		return
+	}
+	var e = Event{CallbackID: r.i.new(), Name: eventNameCmd, Partition: PtrStr(r.partition), TargetID: r.id, WebRequestFilter: f}
+	if f != nil {
+		r.m.Lock()
+		e.Timeout = PtrInt(int(r.timeout / time.Millisecond))
+		e.TimeoutPolicy = r.timeoutPolicy
+		r.m.Unlock()
+	}
+	_, err = synchronousEventWithContext(r.ctx, r.c, r, r.w, e, eventNameDone)
+	return
+}
+
+// setHandler stores the handler of the event and registers the filter on the JS side
+func (r *WebRequest) setHandler(f WebRequestFilter, h WebRequestHandler, eventName, eventNameCmd, eventNameDone string) error {
+	r.m.Lock()
+	if h == nil {
+		delete(r.handlers, eventName)
+	} else {
+		r.handlers[eventName] = webRequestHandler{h: h, timeout: r.timeout}
+	}
+	r.m.Unlock()
+	if h == nil {
+		return r.register(nil, eventNameCmd, eventNameDone)
+	}
+	return r.register(&f, eventNameCmd, eventNameDone)
+}
+
+// setListener stores the listener of the event and registers the filter on the JS side
+func (r *WebRequest) setListener(f WebRequestFilter, l WebRequestListener, eventName, eventNameCmd, eventNameDone string) error {
+	r.m.Lock()
+	if l == nil {
+		delete(r.listeners, eventName)
+	} else {
+		r.listeners[eventName] = l
+	}
+	r.m.Unlock()
+	if l == nil {
+		return r.register(nil, eventNameCmd, eventNameDone)
+	}
+	return r.register(&f, eventNameCmd, eventNameDone)
+}
+
+// OnBeforeRequest executes the handler when a request is about to occur
+// The handler can cancel or redirect the request
+func (r *WebRequest) OnBeforeRequest(f WebRequestFilter, h WebRequestHandler) error {
+	return r.setHandler(f, h, EventNameSessionEventWebRequestBeforeRequest, EventNameSessionCmdWebRequestOnBeforeRequest, EventNameSessionEventWebRequestBeforeRequestHandlerSet)
+}
+
+// OnBeforeSendHeaders executes the handler before sending the request headers
+// The handler can cancel the request or modify its headers
+func (r *WebRequest) OnBeforeSendHeaders(f WebRequestFilter, h WebRequestHandler) error {
+	return r.setHandler(f, h, EventNameSessionEventWebRequestBeforeSendHeaders, EventNameSessionCmdWebRequestOnBeforeSendHeaders, EventNameSessionEventWebRequestBeforeSendHeadersHandlerSet)
+}
+
+// OnCompleted executes the listener when a request is completed
+func (r *WebRequest) OnCompleted(f WebRequestFilter, l WebRequestListener) error {
+	return r.setListener(f, l, EventNameSessionEventWebRequestCompleted, EventNameSessionCmdWebRequestOnCompleted, EventNameSessionEventWebRequestCompletedListenerSet)
+}
+
+// OnErrorOccurred executes the listener when an error occurs
+func (r *WebRequest) OnErrorOccurred(f WebRequestFilter, l WebRequestListener) error {
+	return r.setListener(f, l, EventNameSessionEventWebRequestErrorOccurred, EventNameSessionCmdWebRequestOnErrorOccurred, EventNameSessionEventWebRequestErrorOccurredListenerSet)
+}
+
+// OnHeadersReceived executes the handler when the response headers have been received
+// The handler can cancel or redirect the request or modify the response headers
+func (r *WebRequest) OnHeadersReceived(f WebRequestFilter, h WebRequestHandler) error {
+	return r.setHandler(f, h, EventNameSessionEventWebRequestHeadersReceived, EventNameSessionCmdWebRequestOnHeadersReceived, EventNameSessionEventWebRequestHeadersReceivedHandlerSet)
+}
@@ -0,0 +1,77 @@
+package astilectron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astitools/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebRequest(t *testing.T) {
+	// Init
+	var c = asticontext.NewCanceller()
+	var d = newDispatcher()
+	var i = newIdentifier()
+	var wrt = &mockedWriter{}
+	var w = newWriter(wrt)
+	var s = newSession(context.Background(), c, d, i, w, "")
+	var r = s.WebRequest()
+
+	// Register
+	assert.Error(t, r.SetTimeoutPolicy(0, WebRequestTimeoutPolicyFailClosed))
+	assert.Error(t, r.SetTimeoutPolicy(time.Second, "invalid"))
+	assert.NoError(t, r.SetTimeoutPolicy(time.Second, WebRequestTimeoutPolicyFailClosed))
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "2", Name: EventNameSessionEventWebRequestBeforeSendHeadersHandlerSet, TargetID: s.id})
+	}
+	err := r.OnBeforeSendHeaders(WebRequestFilter{URLs: []string{"https://*.github.com/*"}}, func(ctx context.Context, d WebRequestDetails) WebRequestResponse {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		d.RequestHeaders["Authorization"] = "Bearer token"
+		return WebRequestResponse{RequestHeaders: d.RequestHeaders}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWebRequestOnBeforeSendHeaders + "\",\"targetID\":\"1\",\"callbackId\":\"2\",\"partition\":\"\",\"timeout\":1000,\"timeoutPolicy\":\"fail-closed\",\"webRequestFilter\":{\"urls\":[\"https://*.github.com/*\"]}}\n"}, wrt.w)
+
+	// Handler
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg = &sync.WaitGroup{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "10", Name: EventNameSessionEventWebRequestBeforeSendHeaders, TargetID: s.id, WebRequestDetails: &WebRequestDetails{ID: 1, RequestHeaders: map[string]string{"Accept": "*/*"}, URL: "https://api.github.com"}})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWebRequestCallback + "\",\"targetID\":\"1\",\"callbackId\":\"10\",\"webRequestResponse\":{\"requestHeaders\":{\"Accept\":\"*/*\",\"Authorization\":\"Bearer token\"}}}\n"}, wrt.w)
+
+	// No handler
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	d.dispatch(Event{CallbackID: "11", Name: EventNameSessionEventWebRequestBeforeRequest, TargetID: s.id, WebRequestDetails: &WebRequestDetails{ID: 2}})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWebRequestCallback + "\",\"targetID\":\"1\",\"callbackId\":\"11\",\"webRequestResponse\":{}}\n"}, wrt.w)
+
+	// Listener is stored before the JS starts intercepting
+	wrt.wg = nil
+	var ch = make(chan WebRequestDetails, 1)
+	wrt.fn = func() {
+		d.dispatch(Event{Name: EventNameSessionEventWebRequestCompleted, TargetID: s.id, WebRequestDetails: &WebRequestDetails{ID: 3, StatusCode: PtrInt(200)}})
+		d.dispatch(Event{CallbackID: "3", Name: EventNameSessionEventWebRequestCompletedListenerSet, TargetID: s.id})
+	}
+	err = r.OnCompleted(WebRequestFilter{}, func(d WebRequestDetails) { ch <- d })
+	assert.NoError(t, err)
+	assert.Equal(t, 3, (<-ch).ID)
+
+	// Remove listener
+	wrt.w = []string{}
+	wrt.fn = func() {
+		d.dispatch(Event{CallbackID: "4", Name: EventNameSessionEventWebRequestCompletedListenerSet, TargetID: s.id})
+	}
+	err = r.OnCompleted(WebRequestFilter{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameSessionCmdWebRequestOnCompleted + "\",\"targetID\":\"1\",\"callbackId\":\"4\",\"partition\":\"\"}\n"}, wrt.w)
+	r.m.Lock()
+	assert.Len(t, r.listeners, 0)
+	r.m.Unlock()
+}
@@ -14,8 +14,11 @@ JS -> GO ：操作单位是"event"
 package astilectron
 
 import (
+	"context"
 	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/asticode/go-astilog"
 	"github.com/asticode/go-astitools/context"
@@ -25,56 +28,150 @@ import (
 
 // Window event names
 const (
-	EventNameWebContentsEventLogin             = "web.contents.event.login"
-	EventNameWebContentsEventLoginCallback     = "web.contents.event.login.callback"
-	EventNameWindowCmdBlur                     = "window.cmd.blur"
-	EventNameWindowCmdCenter                   = "window.cmd.center"
-	EventNameWindowCmdClose                    = "window.cmd.close"
-	EventNameWindowCmdCreate                   = "window.cmd.create"
-	EventNameWindowCmdDestroy                  = "window.cmd.destroy"
-	EventNameWindowCmdFocus                    = "window.cmd.focus"
-	EventNameWindowCmdHide                     = "window.cmd.hide"
-	EventNameWindowCmdLog                      = "window.cmd.log"
-	EventNameWindowCmdMaximize                 = "window.cmd.maximize"
-	eventNameWindowCmdMessage                  = "window.cmd.message"
-	eventNameWindowCmdMessageCallback          = "window.cmd.message.callback"
-	EventNameWindowCmdMinimize                 = "window.cmd.minimize"
-	EventNameWindowCmdMove                     = "window.cmd.move"
-	EventNameWindowCmdResize                   = "window.cmd.resize"
-	EventNameWindowCmdRestore                  = "window.cmd.restore"
-	EventNameWindowCmdSetBounds                = "window.cmd.setbounds"
-	EventNameWindowCmdGetBounds                = "window.cmd.getbounds"
-	EventNameWindowCmdSetTitle                 = "window.cmd.settitle"
-	EventNameWindowCmdGetTitle                 = "window.cmd.gettitle"
-	EventNameWindowCmdHook                     = "window.cmd.hook"
-	EventNameWindowCmdShow                     = "window.cmd.show"
-	EventNameWindowCmdUnmaximize               = "window.cmd.unmaximize"
-	EventNameWindowCmdWebContentsCloseDevTools = "window.cmd.web.contents.close.dev.tools"
-	EventNameWindowCmdWebContentsOpenDevTools  = "window.cmd.web.contents.open.dev.tools"
-	EventNameWindowEventBlur                   = "window.event.blur"
-	EventNameWindowEventClosed                 = "window.event.closed"
-	EventNameWindowEventDidFinishLoad          = "window.event.did.finish.load"
-	EventNameWindowEventFocus                  = "window.event.focus"
-	EventNameWindowEventHide                   = "window.event.hide"
-	EventNameWindowEventMaximize               = "window.event.maximize"
-	eventNameWindowEventMessage                = "window.event.message"
-	eventNameWindowEventMessageCallback        = "window.event.message.callback"
-	EventNameWindowEventMinimize               = "window.event.minimize"
-	EventNameWindowEventMove                   = "window.event.move"
-	EventNameWindowEventReadyToShow            = "window.event.ready.to.show"
-	EventNameWindowEventResize                 = "window.event.resize"
-	EventNameWindowEventRestore                = "window.event.restore"
-	EventNameWindowEventShow                   = "window.event.show"
-	EventNameWindowEventUnmaximize             = "window.event.unmaximize"
-	EventNameWindowEventSetBounds              = "window.event.setbounds"
-	EventNameWindowEventGetBounds              = "window.event.getbounds"
-	EventNameWindowEventSetTitle               = "window.event.settitle"
-	EventNameWindowEventGetTitle               = "window.event.gettitle"
-	EventNameWindowEventUnresponsive           = "window.event.unresponsive"
-	EventNameWindowEventDidGetRedirectRequest  = "window.event.did.get.redirect.request"
-	EventNameWindowEventWillNavigate           = "window.event.will.navigate"
-	EventNameWindowEventSystemAwake            = "window.event.system.awake"
-	EventNameWindowEventSystemShutdown         = "window.event.system.shutdown"
+	EventNameWebContentsEventLogin              = "web.contents.event.login"
+	EventNameWebContentsEventLoginCallback      = "web.contents.event.login.callback"
+	EventNameWindowCmdBlur                      = "window.cmd.blur"
+	EventNameWindowCmdCenter                    = "window.cmd.center"
+	EventNameWindowCmdClose                     = "window.cmd.close"
+	EventNameWindowCmdCloseCallback             = "window.cmd.close.callback"
+	EventNameWindowCmdCreate                    = "window.cmd.create"
+	EventNameWindowCmdDestroy                   = "window.cmd.destroy"
+	EventNameWindowCmdFindInPage                = "window.cmd.find.in.page"
+	EventNameWindowCmdFlashFrame                = "window.cmd.flash.frame"
+	EventNameWindowCmdFocus                     = "window.cmd.focus"
+	EventNameWindowCmdHide                      = "window.cmd.hide"
+	EventNameWindowCmdInterceptClose            = "window.cmd.intercept.close"
+	EventNameWindowCmdLog                       = "window.cmd.log"
+	EventNameWindowCmdMaximize                  = "window.cmd.maximize"
+	eventNameWindowCmdMessage                   = "window.cmd.message"
+	eventNameWindowCmdMessageCallback           = "window.cmd.message.callback"
+	EventNameWindowCmdMinimize                  = "window.cmd.minimize"
+	EventNameWindowCmdMove                      = "window.cmd.move"
+	EventNameWindowCmdResize                    = "window.cmd.resize"
+	EventNameWindowCmdRestore                   = "window.cmd.restore"
+	EventNameWindowCmdSetAlwaysOnTop            = "window.cmd.set.always.on.top"
+	EventNameWindowCmdSetBackgroundColor        = "window.cmd.set.background.color"
+	EventNameWindowCmdSetClosable               = "window.cmd.set.closable"
+	EventNameWindowCmdSetFullScreen             = "window.cmd.set.full.screen"
+	EventNameWindowCmdSetIcon                   = "window.cmd.set.icon"
+	EventNameWindowCmdSetKiosk                  = "window.cmd.set.kiosk"
+	EventNameWindowCmdSetMaximizable            = "window.cmd.set.maximizable"
+	EventNameWindowCmdSetMaximumSize            = "window.cmd.set.maximum.size"
+	EventNameWindowCmdSetMinimizable            = "window.cmd.set.minimizable"
+	EventNameWindowCmdSetMinimumSize            = "window.cmd.set.minimum.size"
+	EventNameWindowCmdSetMovable                = "window.cmd.set.movable"
+	EventNameWindowCmdSetOpacity                = "window.cmd.set.opacity"
+	EventNameWindowCmdSetParent                 = "window.cmd.set.parent"
+	EventNameWindowCmdSetProgressBar            = "window.cmd.set.progress.bar"
+	EventNameWindowCmdSetResizable              = "window.cmd.set.resizable"
+	EventNameWindowCmdSetSkipTaskbar            = "window.cmd.set.skip.taskbar"
+	EventNameWindowCmdSetBounds                 = "window.cmd.setbounds"
+	EventNameWindowCmdGetBounds                 = "window.cmd.getbounds"
+	EventNameWindowCmdGetState                  = "window.cmd.get.state"
+	EventNameWindowCmdSetTitle                  = "window.cmd.settitle"
+	EventNameWindowCmdGetTitle                  = "window.cmd.gettitle"
+	EventNameWindowCmdGetZoomFactor             = "window.cmd.get.zoom.factor"
+	EventNameWindowCmdSetVisualZoomLimits       = "window.cmd.set.visual.zoom.limits"
+	EventNameWindowCmdSetZoomFactor             = "window.cmd.set.zoom.factor"
+	EventNameWindowCmdSetZoomLevel              = "window.cmd.set.zoom.level"
+	EventNameWindowCmdHook                      = "window.cmd.hook"
+	EventNameWindowCmdShow                      = "window.cmd.show"
+	EventNameWindowCmdStopFindInPage            = "window.cmd.stop.find.in.page"
+	EventNameWindowCmdUnmaximize                = "window.cmd.unmaximize"
+	EventNameWindowCmdWebContentsCapturePage    = "window.cmd.web.contents.capture.page"
+	EventNameWindowCmdWebContentsCloseDevTools  = "window.cmd.web.contents.close.dev.tools"
+	EventNameWindowCmdWebContentsOpenDevTools   = "window.cmd.web.contents.open.dev.tools"
+	EventNameWindowCmdWebContentsPrint          = "window.cmd.web.contents.print"
+	EventNameWindowCmdWebContentsPrintToPDF     = "window.cmd.web.contents.print.to.pdf"
+	EventNameWindowEventBlur                    = "window.event.blur"
+	EventNameWindowEventClose                   = "window.event.close"
+	EventNameWindowEventCloseVetoed             = "window.event.close.vetoed"
+	EventNameWindowEventClosed                  = "window.event.closed"
+	EventNameWindowEventDidFinishLoad           = "window.event.did.finish.load"
+	EventNameWindowEventEnterFullScreen         = "window.event.enter.full.screen"
+	EventNameWindowEventFindInPage              = "window.event.find.in.page"
+	EventNameWindowEventFlashFrame              = "window.event.flash.frame"
+	EventNameWindowEventFocus                   = "window.event.focus"
+	EventNameWindowEventFoundInPage             = "window.event.found.in.page"
+	EventNameWindowEventHide                    = "window.event.hide"
+	EventNameWindowEventLeaveFullScreen         = "window.event.leave.full.screen"
+	EventNameWindowEventMaximize                = "window.event.maximize"
+	eventNameWindowEventMessage                 = "window.event.message"
+	eventNameWindowEventMessageCallback         = "window.event.message.callback"
+	EventNameWindowEventMinimize                = "window.event.minimize"
+	EventNameWindowEventModalResult             = "window.event.modal.result"
+	EventNameWindowEventMove                    = "window.event.move"
+	EventNameWindowEventReadyToShow             = "window.event.ready.to.show"
+	EventNameWindowEventResize                  = "window.event.resize"
+	EventNameWindowEventRestore                 = "window.event.restore"
+	EventNameWindowEventSetAlwaysOnTop          = "window.event.set.always.on.top"
+	EventNameWindowEventSetBackgroundColor      = "window.event.set.background.color"
+	EventNameWindowEventSetClosable             = "window.event.set.closable"
+	EventNameWindowEventSetFullScreen           = "window.event.set.full.screen"
+	EventNameWindowEventSetIcon                 = "window.event.set.icon"
+	EventNameWindowEventSetKiosk                = "window.event.set.kiosk"
+	EventNameWindowEventSetMaximizable          = "window.event.set.maximizable"
+	EventNameWindowEventSetMaximumSize          = "window.event.set.maximum.size"
+	EventNameWindowEventSetMinimizable          = "window.event.set.minimizable"
+	EventNameWindowEventSetMinimumSize          = "window.event.set.minimum.size"
+	EventNameWindowEventSetMovable              = "window.event.set.movable"
+	EventNameWindowEventSetOpacity              = "window.event.set.opacity"
+	EventNameWindowEventSetParent               = "window.event.set.parent"
+	EventNameWindowEventSetProgressBar          = "window.event.set.progress.bar"
+	EventNameWindowEventSetResizable            = "window.event.set.resizable"
+	EventNameWindowEventSetSkipTaskbar          = "window.event.set.skip.taskbar"
+	EventNameWindowEventShow                    = "window.event.show"
+	EventNameWindowEventStopFindInPage          = "window.event.stop.find.in.page"
+	EventNameWindowEventUnmaximize              = "window.event.unmaximize"
+	EventNameWindowEventWebContentsCapturedPage = "window.event.web.contents.captured.page"
+	EventNameWindowEventWebContentsPrinted      = "window.event.web.contents.printed"
+	EventNameWindowEventWebContentsPrintedToPDF = "window.event.web.contents.printed.to.pdf"
+	EventNameWindowEventSetBounds               = "window.event.setbounds"
+	EventNameWindowEventGetBounds               = "window.event.getbounds"
+	EventNameWindowEventGetState                = "window.event.get.state"
+	EventNameWindowEventSetTitle                = "window.event.settitle"
+	EventNameWindowEventGetTitle                = "window.event.gettitle"
+	EventNameWindowEventGetZoomFactor           = "window.event.get.zoom.factor"
+	EventNameWindowEventSetVisualZoomLimits     = "window.event.set.visual.zoom.limits"
+	EventNameWindowEventSetZoomFactor           = "window.event.set.zoom.factor"
+	EventNameWindowEventSetZoomLevel            = "window.event.set.zoom.level"
+	EventNameWindowEventUnresponsive            = "window.event.unresponsive"
+	EventNameWindowEventDidGetRedirectRequest   = "window.event.did.get.redirect.request"
+	EventNameWindowEventWillNavigate            = "window.event.will.navigate"
+	EventNameWindowEventSystemAwake             = "window.event.system.awake"
+	EventNameWindowEventSystemShutdown          = "window.event.system.shutdown"
+	EventNameWindowEventZoomChanged             = "window.event.zoom.changed"
+)
+
+// Default delay after which debounced bounds listeners are executed once the user stops moving or resizing a window
+const DefaultWindowBoundsDebounceDelay = 250 * time.Millisecond
+
+// Image formats
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png"
+)
+
+// Zoom directions
+const (
+	ZoomDirectionIn  = "in"
+	ZoomDirectionOut = "out"
+)
+
+// Progress bar modes
+const (
+	ProgressBarModeError         = "error"
+	ProgressBarModeIndeterminate = "indeterminate"
+	ProgressBarModeNone          = "none"
+	ProgressBarModeNormal        = "normal"
+	ProgressBarModePaused        = "paused"
+)
+
+// Stop find in page actions
+const (
+	StopFindInPageActionActivateSelection = "activateSelection"
+	StopFindInPageActionClearSelection    = "clearSelection"
+	StopFindInPageActionKeepSelection     = "keepSelection"
 )
 
 // Title bar styles
@@ -84,17 +181,29 @@ var (
 	TitleBarStyleHiddenInset = PtrStr("hidden-inset")
 )
 
+// Vars
+var (
+	ErrWindowCloseVetoed = errors.New("window.close.vetoed")
+)
+
 // Window represents a window
 // TODO Add missing window options
 // TODO Add missing window methods
 // TODO Add missing window events
 type Window struct {
 	*object
+	beforeClose        func(ctx context.Context) (allow bool) // Locked by m
+	beforeCloseTimeout time.Duration
 	callbackIdentifier *identifier
-	m                  sync.Mutex // Locks o
+	children           []*Window          // Locked by m
+	created            bool               // Locked by m
+	m                  sync.Mutex         // Locks o
+	modalResult        chan *EventMessage // Locked by m
 	o                  *WindowOptions
 	onMessageOnce      sync.Once
+	parent             *Window // Locked by m
 	Session            *Session
+	state              WindowState // Locked by m
 	url                *url.URL
 }
 
@@ -127,6 +236,7 @@ type WindowOptions struct {
 	MinWidth               *int            `json:"minWidth,omitempty"`
 	Modal                  *bool           `json:"modal,omitempty"`
 	Movable                *bool           `json:"movable,omitempty"`
+	Opacity                *float64        `json:"opacity,omitempty"`
 	Resizable              *bool           `json:"resizable,omitempty"`
 	Show                   *bool           `json:"show,omitempty"`
 	SkipTaskbar            *bool           `json:"skipTaskbar,omitempty"`
@@ -140,10 +250,11 @@ type WindowOptions struct {
 	Y                      *int            `json:"y,omitempty"`
 
 	// Additional options
-	Custom *WindowCustomOptions `json:"custom,omitempty"`
-	Load   *WindowLoadOptions   `json:"load,omitempty"`
-	Proxy  *WindowProxyOptions  `json:"proxy,omitempty"`
+	Custom     *WindowCustomOptions `json:"custom,omitempty"`
+	Load       *WindowLoadOptions   `json:"load,omitempty"`
+	Proxy      *WindowProxyOptions  `json:"proxy,omitempty"`
 	AppDetails *WindowAppDetails    `json:"appDetails,omitempty"`
+	Session    *Session             `json:"-"` // Shared session created with Astilectron.Session
 }
 
 // WindowAppDetails represents window app details
@@ -164,6 +275,41 @@ type WindowCustomOptions struct {
 	Script            string             `json:"script,omitempty"`
 }
 
+// CapturePageOptions represents capture page options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#contentscapturepagerect-callback
+type CapturePageOptions struct {
+	Format  string            `json:"format,omitempty"`
+	Quality *int              `json:"quality,omitempty"` // Only used by the JPEG format, between 0 and 100
+	Rect    *RectangleOptions `json:"rect,omitempty"`
+}
+
+// FindInPageOptions represents find in page options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#contentsfindinpagetext-options
+type FindInPageOptions struct {
+	FindNext                 *bool `json:"findNext,omitempty"`
+	Forward                  *bool `json:"forward,omitempty"`
+	MatchCase                *bool `json:"matchCase,omitempty"`
+	MedialCapitalAsWordStart *bool `json:"medialCapitalAsWordStart,omitempty"`
+	WordStart                *bool `json:"wordStart,omitempty"`
+}
+
+// FoundInPageResult represents the result of a find in page request
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#event-found-in-page
+type FoundInPageResult struct {
+	ActiveMatchOrdinal int               `json:"activeMatchOrdinal"`
+	FinalUpdate        bool              `json:"finalUpdate"`
+	Matches            int               `json:"matches"`
+	RequestID          int               `json:"requestId"`
+	SelectionArea      *RectangleOptions `json:"selectionArea,omitempty"`
+}
+
+// ProgressBarOptions represents progress bar options
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/browser-window.md#winsetprogressbarprogress-options
+type ProgressBarOptions struct {
+	Mode     string  `json:"mode,omitempty"`
+	Progress float64 `json:"progress"`
+}
+
 // WindowLoadOptions represents window load options
 // https://github.com/electron/electron/blob/v1.8.1/docs/api/browser-window.md#winloadurlurl-options
 type WindowLoadOptions struct {
@@ -215,15 +361,54 @@ type WebPreferences struct {
 	ZoomFactor                  *float64               `json:"zoomFactor,omitempty"`
 }
 
+// WindowState represents the live state of a window
+type WindowState struct {
+	Bounds        *RectangleOptions `json:"bounds,omitempty"`
+	ContentBounds *RectangleOptions `json:"contentBounds,omitempty"`
+	IsAlwaysOnTop bool              `json:"isAlwaysOnTop"`
+	IsFocused     bool              `json:"isFocused"`
+	IsFullScreen  bool              `json:"isFullScreen"`
+	IsMaximized   bool              `json:"isMaximized"`
+	IsMinimized   bool              `json:"isMinimized"`
+	IsVisible     bool              `json:"isVisible"`
+	Title         string            `json:"title,omitempty"`
+	URL           string            `json:"url,omitempty"`
+}
+
+// ZoomLevelLimits represents zoom level limits
+// https://github.com/electron/electron/blob/v1.8.1/docs/api/web-contents.md#contentssetvisualzoomlevellimitsminimumlevel-maximumlevel
+type ZoomLevelLimits struct {
+	Maximum float64 `json:"maximum"`
+	Minimum float64 `json:"minimum"`
+}
+
 // newWindow creates a new window
 func newWindow(o Options, p Paths, url string, wo *WindowOptions, c *asticontext.Canceller, d *dispatcher, i *identifier, wrt *writer) (w *Window, err error) {
 	// Init
 	w = &Window{
-		callbackIdentifier: newIdentifier(),        // 此成员用于 go 和 js 之间的消息回送，用一个 id 作为标记
+		callbackIdentifier: newIdentifier(), // 此成员用于 go 和 js 之间的消息回送，用一个 id 作为标记
 		o:                  wo,
 		object:             newObject(nil, c, d, i, wrt, i.new()),
 	}
-	w.Session = newSession(w.ctx, c, d, i, wrt)
+	if wo.Session != nil {
+		w.Session = wo.Session
+		if wo.WebPreferences == nil {
+			wo.WebPreferences = &WebPreferences{}
+		}
+		wo.WebPreferences.Partition = PtrStr(wo.Session.partition)
+	} else {
+		var partition string
+		if wo.WebPreferences != nil && wo.WebPreferences.Partition != nil {
+			partition = *wo.WebPreferences.Partition
+		}
+		w.Session = newSession(w.ctx, c, d, i, wrt, partition)
+	}
+
+	// Before close timeout
+	w.beforeCloseTimeout = o.BeforeCloseTimeout
+	if w.beforeCloseTimeout <= 0 {
+		w.beforeCloseTimeout = DefaultBeforeCloseTimeout
+	}
 
 	// Check app details
 	if wo.Icon == nil && p.AppIconDefaultSrc() != "" {
@@ -236,24 +421,72 @@ func newWindow(o Options, p Paths, url string, wo *WindowOptions, c *asticontext
 
 	// Make sure the window's context is cancelled once the closed event is received
 	w.On(EventNameWindowEventClosed, func(e Event) (deleteListener bool) {
-		w.cancel()
+		w.closed()
 		return true
 	})
 
+	// Before close
+	w.On(EventNameWindowEventClose, w.onClose)
+
+	// Modal result
+	w.On(EventNameWindowEventModalResult, func(e Event) (deleteListener bool) {
+		w.m.Lock()
+		defer w.m.Unlock()
+		if w.modalResult != nil {
+			select {
+			case w.modalResult <- e.Message:
+			default:
+			}
+		}
+		return
+	})
+
 	// Show
 	w.On(EventNameWindowEventHide, func(e Event) (deleteListener bool) {
 		w.m.Lock()
 		defer w.m.Unlock()
 		w.o.Show = PtrBool(false)
+		w.state.IsVisible = false
 		return
 	})
 	w.On(EventNameWindowEventShow, func(e Event) (deleteListener bool) {
 		w.m.Lock()
 		defer w.m.Unlock()
 		w.o.Show = PtrBool(true)
+		w.state.IsVisible = true
+		return
+	})
+
+	// Bounds
+	w.On(EventNameWindowEventMove, func(e Event) (deleteListener bool) {
+		if e.Bounds != nil {
+			w.updateBounds(*e.Bounds)
+		}
+		return
+	})
+	w.On(EventNameWindowEventResize, func(e Event) (deleteListener bool) {
+		if e.Bounds != nil {
+			w.updateBounds(*e.Bounds)
+		}
 		return
 	})
 
+	// State
+	w.state = WindowState{
+		IsAlwaysOnTop: wo.AlwaysOnTop != nil && *wo.AlwaysOnTop,
+		IsFullScreen:  wo.Fullscreen != nil && *wo.Fullscreen,
+		IsVisible:     wo.Show != nil && *wo.Show,
+		URL:           url,
+	}
+	w.onStateEvent(EventNameWindowEventBlur, func(s *WindowState) { s.IsFocused = false })
+	w.onStateEvent(EventNameWindowEventEnterFullScreen, func(s *WindowState) { s.IsFullScreen = true })
+	w.onStateEvent(EventNameWindowEventFocus, func(s *WindowState) { s.IsFocused = true })
+	w.onStateEvent(EventNameWindowEventLeaveFullScreen, func(s *WindowState) { s.IsFullScreen = false })
+	w.onStateEvent(EventNameWindowEventMaximize, func(s *WindowState) { s.IsMaximized = true })
+	w.onStateEvent(EventNameWindowEventMinimize, func(s *WindowState) { s.IsMinimized = true })
+	w.onStateEvent(EventNameWindowEventRestore, func(s *WindowState) { s.IsMinimized = false })
+	w.onStateEvent(EventNameWindowEventUnmaximize, func(s *WindowState) { s.IsMaximized = false })
+
 	// Parse url
 	if w.url, err = astiurl.Parse(url); err != nil {
 		err = errors.Wrapf(err, "parsing url %s failed", url)
@@ -277,6 +510,25 @@ func (w *Window) Blur() (err error) {
 	return
 }
 
+// CapturePage captures a snapshot of the page within the rectangle and returns it encoded in PNG
+// If the rectangle is nil, the whole visible page is captured
+func (w *Window) CapturePage(ctx context.Context, r *RectangleOptions) ([]byte, error) {
+	return w.CapturePageWithOptions(ctx, CapturePageOptions{Format: ImageFormatPNG, Rect: r})
+}
+
+// CapturePageWithOptions captures a snapshot of the page and returns it encoded in the requested format
+// The image is sent back in several base64 chunks so that it never breaks the newline-delimited protocol
+func (w *Window) CapturePageWithOptions(ctx context.Context, o CapturePageOptions) (b []byte, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	if len(o.Format) == 0 {
+		o.Format = ImageFormatPNG
+	}
+	b, err = synchronousChunkedEvent(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), CapturePageOptions: &o, Name: EventNameWindowCmdWebContentsCapturePage, TargetID: w.id}, EventNameWindowEventWebContentsCapturedPage)
+	return
+}
+
 // Center centers the window
 func (w *Window) Center() (err error) {
 	if err = w.isActionable(); err != nil {
@@ -286,12 +538,54 @@ func (w *Window) Center() (err error) {
 	return
 }
 
+// CachedState returns the last known state of the window without querying Electron
+// It is updated by State and whenever a state related event is received
+func (w *Window) CachedState() WindowState {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.state
+}
+
+// Children returns the child windows
+func (w *Window) Children() []*Window {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return append([]*Window{}, w.children...)
+}
+
 // Close closes the window
+// ErrWindowCloseVetoed is returned if the OnBeforeClose handler doesn't allow the window to close
 func (w *Window) Close() (err error) {
 	if err = w.isActionable(); err != nil {
 		return
 	}
-	_, err = synchronousEvent(w.c, w, w.w, Event{Name: EventNameWindowCmdClose, TargetID: w.id}, EventNameWindowEventClosed)
+	var ctx, cancel = w.c.NewContext()
+	defer cancel()
+	var ch = make(chan error, 1)
+	var done int32
+	defer atomic.StoreInt32(&done, 1)
+	var l = func(r error) Listener {
+		return func(e Event) (deleteListener bool) {
+			if atomic.LoadInt32(&done) == 0 {
+				select {
+				case ch <- r:
+				default:
+				}
+			}
+			return true
+		}
+	}
+	w.On(EventNameWindowEventClosed, l(nil))
+	w.On(EventNameWindowEventCloseVetoed, l(ErrWindowCloseVetoed))
+	if err = w.w.write(Event{Name: EventNameWindowCmdClose, TargetID: w.id}); err != nil {
+		err = errors.Wrap(err, "writing close event failed")
+		return
+	}
+	select {
+	case err = <-ch:
+	case <-ctx.Done():
+		err = ErrCancellerCancelled
+	}
 	return
 }
 
@@ -311,7 +605,17 @@ func (w *Window) Create() (err error) {
 	if err = w.isActionable(); err != nil {
 		return
 	}
-	_, err = synchronousEvent(w.c, w, w.w, Event{Name: EventNameWindowCmdCreate, SessionID: w.Session.id, TargetID: w.id, URL: w.url.String(), WindowOptions: w.o}, EventNameWindowEventDidFinishLoad)
+	var e = Event{Name: EventNameWindowCmdCreate, SessionID: w.Session.id, TargetID: w.id, URL: w.url.String(), WindowOptions: w.o}
+	if p := w.Parent(); p != nil {
+		e.ParentID = p.id
+	}
+	w.m.Lock()
+	w.created = true
+	if w.beforeClose != nil {
+		e.Timeout = PtrInt(int(w.beforeCloseTimeout / time.Millisecond))
+	}
+	w.m.Unlock()
+	_, err = synchronousEvent(w.c, w, w.w, e, EventNameWindowEventDidFinishLoad)
 	return
 }
 
@@ -324,6 +628,31 @@ func (w *Window) Destroy() (err error) {
 	return
 }
 
+// FindInPage starts a request to find all matches for the text in the web page and returns the request ID
+// Results are sent through the EventNameWindowEventFoundInPage event, see OnFoundInPage
+func (w *Window) FindInPage(ctx context.Context, text string, o FindInPageOptions) (requestID int, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), FindInPageOptions: &o, Name: EventNameWindowCmdFindInPage, TargetID: w.id, Text: text}, EventNameWindowEventFindInPage); err != nil {
+		return
+	}
+	if e.ID != nil {
+		requestID = *e.ID
+	}
+	return
+}
+
+// FlashFrame starts or stops flashing the window to attract the user's attention
+func (w *Window) FlashFrame(ctx context.Context, flag bool) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Flash: PtrBool(flag), Name: EventNameWindowCmdFlashFrame, TargetID: w.id}, EventNameWindowEventFlashFrame)
+	return
+}
+
 // Focus focuses on the window
 func (w *Window) Focus() (err error) {
 	if err = w.isActionable(); err != nil {
@@ -396,17 +725,21 @@ func (w *Window) MoveInDisplay(d *Display, x, y int) error {
 	return w.Move(d.Bounds().X+x, d.Bounds().Y+y)
 }
 
+// OnLogin sets the handler providing the credentials of the window's HTTP and proxy authentications
+// Returning an error or empty credentials cancels the authentication
 func (w *Window) OnLogin(fn func(i Event) (username, password string, err error)) {
 	w.On(EventNameWebContentsEventLogin, func(i Event) (deleteListener bool) {
 		// Get username and password
 		username, password, err := fn(i)
 		if err != nil {
 			astilog.Error(errors.Wrap(err, "getting username and password failed"))
-			return
 		}
 
 		// No auth
-		if len(username) == 0 && len(password) == 0 {
+		if err != nil || (len(username) == 0 && len(password) == 0) {
+			if err = w.w.write(Event{Allow: PtrBool(false), CallbackID: i.CallbackID, Name: EventNameWebContentsEventLoginCallback, TargetID: w.id}); err != nil {
+				astilog.Error(errors.Wrap(err, "writing login callback message failed"))
+			}
 			return
 		}
 
@@ -445,6 +778,115 @@ func (w *Window) OnMessage(l ListenerMessage) {
 	})
 }
 
+// OnMoved adds a listener executed with the new bounds once the window has stopped moving for the delay
+// If the delay is not strictly positive, DefaultWindowBoundsDebounceDelay is used
+func (w *Window) OnMoved(delay time.Duration, fn func(bounds RectangleOptions)) {
+	w.onDebouncedBounds(EventNameWindowEventMove, delay, fn)
+}
+
+// OnResized adds a listener executed with the new bounds once the window has stopped being resized for the delay
+// If the delay is not strictly positive, DefaultWindowBoundsDebounceDelay is used
+func (w *Window) OnResized(delay time.Duration, fn func(bounds RectangleOptions)) {
+	w.onDebouncedBounds(EventNameWindowEventResize, delay, fn)
+}
+
+// onDebouncedBounds adds a listener executed with the last received bounds once no event has been received for the delay
+func (w *Window) onDebouncedBounds(eventName string, delay time.Duration, fn func(bounds RectangleOptions)) {
+	if delay <= 0 {
+		delay = DefaultWindowBoundsDebounceDelay
+	}
+	var b RectangleOptions
+	var m sync.Mutex
+	var t *time.Timer
+	w.On(eventName, func(e Event) (deleteListener bool) {
+		if e.Bounds == nil {
+			return
+		}
+		m.Lock()
+		defer m.Unlock()
+		b = *e.Bounds
+		if t != nil {
+			t.Stop()
+		}
+		t = time.AfterFunc(delay, func() {
+			if w.IsDestroyed() {
+				return
+			}
+			m.Lock()
+			var bounds = b
+			m.Unlock()
+			fn(bounds)
+		})
+		return
+	})
+}
+
+// onStateEvent adds a listener updating the cached state when receiving a specific event
+func (w *Window) onStateEvent(eventName string, fn func(s *WindowState)) {
+	w.On(eventName, func(e Event) (deleteListener bool) {
+		w.m.Lock()
+		defer w.m.Unlock()
+		fn(&w.state)
+		return
+	})
+}
+
+// OnBeforeClose sets the handler deciding whether the window is allowed to close, a nil handler removing it
+// Electron waits for the decision which is made within Options.BeforeCloseTimeout, the window closing otherwise.
+// Close triggers the handler as well whereas Destroy doesn't.
+func (w *Window) OnBeforeClose(fn func(ctx context.Context) (allow bool)) (err error) {
+	w.m.Lock()
+	w.beforeClose = fn
+	var created = w.created
+	w.m.Unlock()
+	if !created {
+		return
+	}
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	var e = Event{Name: EventNameWindowCmdInterceptClose, TargetID: w.id}
+	if fn != nil {
+		e.Timeout = PtrInt(int(w.beforeCloseTimeout / time.Millisecond))
+	}
+	err = w.w.write(e)
+	return
+}
+
+// onClose sends the before close handler's decision back to Electron
+func (w *Window) onClose(e Event) (deleteListener bool) {
+	// Electron only waits for a decision when the close is intercepted
+	if e.CallbackID == "" {
+		return
+	}
+	w.m.Lock()
+	var fn = w.beforeClose
+	w.m.Unlock()
+	var allow = true
+	if fn != nil {
+		allow = closeDecision(w.beforeCloseTimeout, fn)
+	}
+	if err := w.w.write(Event{Allow: PtrBool(allow), CallbackID: e.CallbackID, Name: EventNameWindowCmdCloseCallback, TargetID: w.id}); err != nil {
+		astilog.Error(errors.Wrap(err, "writing close callback message failed"))
+	}
+
+	// Let Close know the window won't close
+	if !allow {
+		w.d.dispatch(Event{Name: EventNameWindowEventCloseVetoed, TargetID: w.id})
+	}
+	return
+}
+
+// OnFoundInPage adds a listener executed when a result is available for a find in page request
+func (w *Window) OnFoundInPage(fn func(r FoundInPageResult)) {
+	w.On(EventNameWindowEventFoundInPage, func(e Event) (deleteListener bool) {
+		if e.FoundInPage != nil {
+			fn(*e.FoundInPage)
+		}
+		return
+	})
+}
+
 // OpenDevTools opens the dev tools
 func (w *Window) OpenDevTools() (err error) {
 	if err = w.isActionable(); err != nil {
@@ -453,6 +895,33 @@ func (w *Window) OpenDevTools() (err error) {
 	return w.w.write(Event{Name: EventNameWindowCmdWebContentsOpenDevTools, TargetID: w.id})
 }
 
+// Parent returns the parent window or nil if the window has no parent
+func (w *Window) Parent() *Window {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.parent
+}
+
+// Print prints the window's web page
+// When Silent is true, the page is sent to the default printer or to DeviceName without asking the user
+func (w *Window) Print(ctx context.Context, o PrintOptions) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdWebContentsPrint, PrintOptions: &o, TargetID: w.id}, EventNameWindowEventWebContentsPrinted)
+	return
+}
+
+// PrintToPDF prints the window's web page as PDF and returns its content
+// The window doesn't need to be shown which allows generating PDFs without the user seeing the rendering
+func (w *Window) PrintToPDF(ctx context.Context, o PrintToPDFOptions) (b []byte, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	b, err = synchronousChunkedEvent(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdWebContentsPrintToPDF, PrintToPDFOptions: &o, TargetID: w.id}, EventNameWindowEventWebContentsPrintedToPDF)
+	return
+}
+
 // Resize resizes the window
 func (w *Window) Resize(width, height int) (err error) {
 	if err = w.isActionable(); err != nil {
@@ -505,6 +974,113 @@ func (w *Window) GetBounds() (r RectangleOptions, err error) {
 	return
 }
 
+// setOptions sends the options o to Electron and updates the window options with fn, executed while holding the lock,
+// once Electron has applied them
+func (w *Window) setOptions(ctx context.Context, eventNameCmd, eventNameDone string, o *WindowOptions, fn func(wo *WindowOptions)) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	if _, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: eventNameCmd, TargetID: w.id, WindowOptions: o}, eventNameDone); err != nil {
+		return
+	}
+	w.m.Lock()
+	fn(w.o)
+	w.m.Unlock()
+	return
+}
+
+// SetAlwaysOnTop sets whether the window should show always on top of other windows
+func (w *Window) SetAlwaysOnTop(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetAlwaysOnTop, EventNameWindowEventSetAlwaysOnTop, &WindowOptions{AlwaysOnTop: PtrBool(flag)}, func(wo *WindowOptions) {
+		wo.AlwaysOnTop = PtrBool(flag)
+		w.state.IsAlwaysOnTop = flag
+	})
+}
+
+// SetBackgroundColor sets the background color of the window such as "#FFF"
+func (w *Window) SetBackgroundColor(ctx context.Context, color string) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetBackgroundColor, EventNameWindowEventSetBackgroundColor, &WindowOptions{BackgroundColor: PtrStr(color)}, func(wo *WindowOptions) { wo.BackgroundColor = PtrStr(color) })
+}
+
+// SetClosable sets whether the window can be manually closed by the user
+func (w *Window) SetClosable(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetClosable, EventNameWindowEventSetClosable, &WindowOptions{Closable: PtrBool(flag)}, func(wo *WindowOptions) { wo.Closable = PtrBool(flag) })
+}
+
+// SetFullScreen sets whether the window should be in fullscreen mode
+func (w *Window) SetFullScreen(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetFullScreen, EventNameWindowEventSetFullScreen, &WindowOptions{Fullscreen: PtrBool(flag)}, func(wo *WindowOptions) {
+		wo.Fullscreen = PtrBool(flag)
+		w.state.IsFullScreen = flag
+	})
+}
+
+// SetIcon sets the icon of the window
+func (w *Window) SetIcon(ctx context.Context, icon string) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetIcon, EventNameWindowEventSetIcon, &WindowOptions{Icon: PtrStr(icon)}, func(wo *WindowOptions) { wo.Icon = PtrStr(icon) })
+}
+
+// SetKiosk sets whether the window should be in kiosk mode
+func (w *Window) SetKiosk(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetKiosk, EventNameWindowEventSetKiosk, &WindowOptions{Kiosk: PtrBool(flag)}, func(wo *WindowOptions) { wo.Kiosk = PtrBool(flag) })
+}
+
+// SetMaximizable sets whether the window can be manually maximized by the user
+func (w *Window) SetMaximizable(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetMaximizable, EventNameWindowEventSetMaximizable, &WindowOptions{Maximizable: PtrBool(flag)}, func(wo *WindowOptions) { wo.Maximizable = PtrBool(flag) })
+}
+
+// SetMaximumSize sets the maximum size of the window
+func (w *Window) SetMaximumSize(ctx context.Context, width, height int) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetMaximumSize, EventNameWindowEventSetMaximumSize, &WindowOptions{MaxHeight: PtrInt(height), MaxWidth: PtrInt(width)}, func(wo *WindowOptions) {
+		wo.MaxHeight = PtrInt(height)
+		wo.MaxWidth = PtrInt(width)
+	})
+}
+
+// SetMinimizable sets whether the window can be manually minimized by the user
+func (w *Window) SetMinimizable(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetMinimizable, EventNameWindowEventSetMinimizable, &WindowOptions{Minimizable: PtrBool(flag)}, func(wo *WindowOptions) { wo.Minimizable = PtrBool(flag) })
+}
+
+// SetMinimumSize sets the minimum size of the window
+func (w *Window) SetMinimumSize(ctx context.Context, width, height int) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetMinimumSize, EventNameWindowEventSetMinimumSize, &WindowOptions{MinHeight: PtrInt(height), MinWidth: PtrInt(width)}, func(wo *WindowOptions) {
+		wo.MinHeight = PtrInt(height)
+		wo.MinWidth = PtrInt(width)
+	})
+}
+
+// SetMovable sets whether the window can be moved by the user
+func (w *Window) SetMovable(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetMovable, EventNameWindowEventSetMovable, &WindowOptions{Movable: PtrBool(flag)}, func(wo *WindowOptions) { wo.Movable = PtrBool(flag) })
+}
+
+// SetOpacity sets the opacity of the window, between 0.0 (fully transparent) and 1.0 (fully opaque)
+func (w *Window) SetOpacity(ctx context.Context, opacity float64) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetOpacity, EventNameWindowEventSetOpacity, &WindowOptions{Opacity: PtrFloat(opacity)}, func(wo *WindowOptions) { wo.Opacity = PtrFloat(opacity) })
+}
+
+// SetProgressBar sets the progress value, between 0 and 1, of the progress bar shown in the taskbar or the dock
+// Use ProgressBarModeNone to remove the progress bar
+func (w *Window) SetProgressBar(ctx context.Context, progress float64, mode string) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdSetProgressBar, ProgressBar: &ProgressBarOptions{Mode: mode, Progress: progress}, TargetID: w.id}, EventNameWindowEventSetProgressBar)
+	return
+}
+
+// SetResizable sets whether the window can be manually resized by the user
+func (w *Window) SetResizable(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetResizable, EventNameWindowEventSetResizable, &WindowOptions{Resizable: PtrBool(flag)}, func(wo *WindowOptions) { wo.Resizable = PtrBool(flag) })
+}
+
+// SetSkipTaskbar sets whether the window should be hidden from the taskbar
+func (w *Window) SetSkipTaskbar(ctx context.Context, flag bool) error {
+	return w.setOptions(ctx, EventNameWindowCmdSetSkipTaskbar, EventNameWindowEventSetSkipTaskbar, &WindowOptions{SkipTaskbar: PtrBool(flag)}, func(wo *WindowOptions) { wo.SkipTaskbar = PtrBool(flag) })
+}
+
 // SetTitle set title of the window
 func (w *Window) SetTitle(title string) (err error) {
 	if err = w.isActionable(); err != nil {
@@ -528,6 +1104,69 @@ func (w *Window) GetTitle() (title string, err error) {
 	return
 }
 
+// GetZoomFactor returns the current zoom factor of the window's web page
+// EventNameWindowEventZoomChanged only holds the zoom direction, call this to know the resulting zoom factor
+func (w *Window) GetZoomFactor(ctx context.Context) (f float64, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdGetZoomFactor, TargetID: w.id}, EventNameWindowEventGetZoomFactor); err != nil {
+		return
+	}
+	if e.ZoomFactor != nil {
+		f = *e.ZoomFactor
+	}
+	return
+}
+
+// SetVisualZoomLevelLimits sets the minimum and maximum levels of pinch-to-zoom
+func (w *Window) SetVisualZoomLevelLimits(ctx context.Context, minimum, maximum float64) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdSetVisualZoomLimits, TargetID: w.id, ZoomLevelLimits: &ZoomLevelLimits{Maximum: maximum, Minimum: minimum}}, EventNameWindowEventSetVisualZoomLimits)
+	return
+}
+
+// SetZoomFactor sets the zoom factor of the window's web page, 1.0 being 100%
+func (w *Window) SetZoomFactor(ctx context.Context, f float64) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdSetZoomFactor, TargetID: w.id, ZoomFactor: PtrFloat(f)}, EventNameWindowEventSetZoomFactor)
+	return
+}
+
+// SetZoomLevel sets the zoom level of the window's web page
+// The original size is 0 and each increment above or below represents zooming 20% larger or smaller
+func (w *Window) SetZoomLevel(ctx context.Context, l float64) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdSetZoomLevel, TargetID: w.id, ZoomLevel: PtrFloat(l)}, EventNameWindowEventSetZoomLevel)
+	return
+}
+
+// updateBounds updates the window options and the cached state with the bounds received from Electron
+func (w *Window) updateBounds(b RectangleOptions) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if b.X != nil {
+		w.o.X = PtrInt(*b.X)
+	}
+	if b.Y != nil {
+		w.o.Y = PtrInt(*b.Y)
+	}
+	if b.Height != nil {
+		w.o.Height = PtrInt(*b.Height)
+	}
+	if b.Width != nil {
+		w.o.Width = PtrInt(*b.Width)
+	}
+	w.state.Bounds = &b
+}
+
 // HookCallbackFunc represents a hook message callback
 type HookCallbackFunc func(wParam uintptr, lParam uintptr)
 
@@ -550,10 +1189,10 @@ func (w *Window) SendMessage(message interface{}, callbacks ...CallbackMessage)
 		return
 	}
 	// 发送 window.cmd.message 事件，回调接收 window.event.message.callback 事件，收到后执行 callbacks
-	var e = Event{Message: newEventMessage(message), Name: eventNameWindowCmdMessage, TargetID: w.id}     // window.cmd.message
+	var e = Event{Message: newEventMessage(message), Name: eventNameWindowCmdMessage, TargetID: w.id} // window.cmd.message
 	if len(callbacks) > 0 {
 		e.CallbackID = w.callbackIdentifier.new()
-		w.On(eventNameWindowEventMessageCallback, func(i Event) (deleteListener bool) {  // window.event.message.callback
+		w.On(eventNameWindowEventMessageCallback, func(i Event) (deleteListener bool) { // window.event.message.callback
 			if i.CallbackID == e.CallbackID {
 				for _, c := range callbacks {
 					c(i.Message)
@@ -566,6 +1205,70 @@ func (w *Window) SendMessage(message interface{}, callbacks ...CallbackMessage)
 	return w.w.write(e)
 }
 
+// SetParent sets the parent of the window, a nil parent turning the window into a top-level window
+func (w *Window) SetParent(p *Window) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	var e = Event{Name: EventNameWindowCmdSetParent, TargetID: w.id}
+	if p != nil {
+		e.ParentID = p.id
+	}
+	if _, err = synchronousEvent(w.c, w, w.w, e, EventNameWindowEventSetParent); err != nil {
+		return
+	}
+	w.setParent(p)
+	return
+}
+
+// setParent updates the relationship between the window and its parent
+func (w *Window) setParent(p *Window) {
+	w.m.Lock()
+	var o = w.parent
+	w.parent = p
+	w.m.Unlock()
+	if o != nil {
+		o.removeChild(w)
+	}
+	if p != nil {
+		p.m.Lock()
+		p.children = append(p.children, w)
+		p.m.Unlock()
+	}
+}
+
+// closed cancels the window's context and detaches it from its parent
+// Electron closes child windows along with their parent, therefore children are marked as closed as well without
+// waiting for their own closed event
+func (w *Window) closed() {
+	w.cancel()
+	w.m.Lock()
+	var children, parent = w.children, w.parent
+	w.children, w.parent = nil, nil
+	w.m.Unlock()
+	if parent != nil {
+		parent.removeChild(w)
+	}
+	for _, c := range children {
+		c.m.Lock()
+		c.parent = nil
+		c.m.Unlock()
+		c.closed()
+	}
+}
+
+// removeChild removes a window from the children
+func (w *Window) removeChild(c *Window) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	for idx, v := range w.children {
+		if v == c {
+			w.children = append(w.children[:idx], w.children[idx+1:]...)
+			return
+		}
+	}
+}
+
 // Show shows the window
 func (w *Window) Show() (err error) {
 	if err = w.isActionable(); err != nil {
@@ -575,6 +1278,77 @@ func (w *Window) Show() (err error) {
 	return
 }
 
+// State queries the live state of the window in one round trip and updates the cached state
+func (w *Window) State(ctx context.Context) (s WindowState, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	var e Event
+	if e, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdGetState, TargetID: w.id}, EventNameWindowEventGetState); err != nil {
+		return
+	}
+	if e.WindowState != nil {
+		s = *e.WindowState
+		w.m.Lock()
+		w.state = s
+		w.m.Unlock()
+	}
+	return
+}
+
+// StopFindInPage stops any find in page request with the provided action
+func (w *Window) StopFindInPage(ctx context.Context, action string) (err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+	_, err = synchronousEventWithContext(ctx, w.c, w, w.w, Event{Action: action, CallbackID: w.callbackIdentifier.new(), Name: EventNameWindowCmdStopFindInPage, TargetID: w.id}, EventNameWindowEventStopFindInPage)
+	return
+}
+
+// ShowModal shows the window and blocks until it is closed or the context is done
+// The renderer posts the result through astilectron.setModalResult in JS which makes ShowModal close the window and
+// return the result. It is nil if the window is closed without a result being posted.
+// If the context is done first, the window is closed as well.
+func (w *Window) ShowModal(ctx context.Context) (m *EventMessage, err error) {
+	if err = w.isActionable(); err != nil {
+		return
+	}
+
+	// Listen
+	var r = make(chan *EventMessage, 1)
+	w.m.Lock()
+	w.modalResult = r
+	w.m.Unlock()
+	defer func() {
+		w.m.Lock()
+		if w.modalResult == r {
+			w.modalResult = nil
+		}
+		w.m.Unlock()
+	}()
+
+	// Show
+	if err = w.Show(); err != nil {
+		err = errors.Wrap(err, "showing window failed")
+		return
+	}
+
+	// Wait
+	select {
+	case m = <-r:
+	case <-w.ctx.Done():
+		return
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// Close the window without waiting for Electron
+	if errClose := w.w.write(Event{Name: EventNameWindowCmdClose, TargetID: w.id}); errClose != nil {
+		astilog.Error(errors.Wrapf(errClose, "closing modal window %s failed", w.id))
+	}
+	return
+}
+
 // Unmaximize unmaximize the window
 func (w *Window) Unmaximize() (err error) {
 	if err = w.isActionable(); err != nil {
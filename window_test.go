@@ -1,8 +1,11 @@
 package astilectron
 
 import (
+	"context"
+	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -54,6 +57,12 @@ func TestWindow_Actions(t *testing.T) {
 	w, err = a.NewWindow("http://test.com", &WindowOptions{})
 	assert.NoError(t, err)
 	testObjectAction(t, func() error { return w.Focus() }, w.object, wrt, "{\"name\":\""+EventNameWindowCmdFocus+"\",\"targetID\":\""+w.id+"\"}\n", EventNameWindowEventFocus)
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameWindowEventFlashFrame, TargetID: w.id})
+	}
+	assert.NoError(t, w.FlashFrame(context.Background(), true))
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdFlashFrame + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"1\",\"flash\":true}\n"}, wrt.w)
 	testObjectAction(t, func() error { return w.Hide() }, w.object, wrt, "{\"name\":\""+EventNameWindowCmdHide+"\",\"targetID\":\""+w.id+"\"}\n", EventNameWindowEventHide)
 	assert.Equal(t, false, w.IsShown())
 	testObjectAction(t, func() error { return w.Log("message") }, w.object, wrt, "{\"name\":\""+EventNameWindowCmdLog+"\",\"targetID\":\""+w.id+"\",\"message\":\"message\"}\n", "")
@@ -79,12 +88,22 @@ func TestWindow_OnLogin(t *testing.T) {
 	w, err := a.NewWindow("http://test.com", &WindowOptions{})
 	assert.NoError(t, err)
 	w.OnLogin(func(i Event) (username, password string, err error) {
+		if i.AuthInfo != nil && i.AuthInfo.Realm == "cancel" {
+			return
+		}
 		return "username", "password", nil
 	})
 	wrt.wg.Add(1)
 	a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameWebContentsEventLogin, TargetID: w.id})
 	wrt.wg.Wait()
 	assert.Equal(t, []string{"{\"name\":\"web.contents.event.login.callback\",\"targetID\":\"1\",\"callbackId\":\"1\",\"password\":\"password\",\"username\":\"username\"}\n"}, wrt.w)
+
+	// Empty credentials cancel the authentication
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{AuthInfo: &EventAuthInfo{Realm: "cancel"}, CallbackID: "2", Name: EventNameWebContentsEventLogin, TargetID: w.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"web.contents.event.login.callback\",\"targetID\":\"1\",\"allow\":false,\"callbackId\":\"2\"}\n"}, wrt.w)
 }
 
 func TestWindow_OnMessage(t *testing.T) {
@@ -136,3 +155,384 @@ func TestWindow_NewMenu(t *testing.T) {
 	m := w.NewMenu([]*MenuItemOptions{})
 	assert.Equal(t, w.id, m.rootID)
 }
+
+func TestWindow_CapturePage(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Data: []byte("image"), Index: PtrInt(0), Name: EventNameWindowEventWebContentsCapturedPage, TargetID: w.id})
+		a.dispatcher.dispatch(Event{CallbackID: "1", Count: PtrInt(1), Name: EventNameWindowEventWebContentsCapturedPage, TargetID: w.id})
+	}
+	b, err := w.CapturePage(context.Background(), &RectangleOptions{SizeOptions: SizeOptions{Height: PtrInt(1), Width: PtrInt(2)}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("image"), b)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdWebContentsCapturePage + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"1\",\"capturePageOptions\":{\"format\":\"png\",\"rect\":{\"height\":1,\"width\":2}}}\n"}, wrt.w)
+}
+
+func TestWindow_Print(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Print
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Error: "no printer", Name: EventNameWindowEventWebContentsPrinted, TargetID: w.id})
+	}
+	err = w.Print(context.Background(), PrintOptions{Silent: PtrBool(true)})
+	assert.EqualError(t, err, "no printer")
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdWebContentsPrint + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"1\",\"printOptions\":{\"silent\":true}}\n"}, wrt.w)
+
+	// Print to PDF
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Data: []byte("pdf"), Index: PtrInt(0), Name: EventNameWindowEventWebContentsPrintedToPDF, TargetID: w.id})
+		a.dispatcher.dispatch(Event{CallbackID: "2", Count: PtrInt(1), Name: EventNameWindowEventWebContentsPrintedToPDF, TargetID: w.id})
+	}
+	b, err := w.PrintToPDF(context.Background(), PrintToPDFOptions{Landscape: PtrBool(true), MarginsType: PtrInt(PrintMarginsTypeNone), PageSize: PrintPageSizeA4})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("pdf"), b)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdWebContentsPrintToPDF + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"2\",\"printToPdfOptions\":{\"landscape\":true,\"marginsType\":1,\"pageSize\":\"A4\"}}\n"}, wrt.w)
+}
+
+func TestWindow_Zoom(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Set
+	var names = []string{EventNameWindowEventSetZoomFactor, EventNameWindowEventSetZoomLevel, EventNameWindowEventSetVisualZoomLimits}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: strconv.Itoa(len(wrt.w)), Name: names[len(wrt.w)-1], TargetID: w.id})
+	}
+	err = w.SetZoomFactor(context.Background(), 1.5)
+	assert.NoError(t, err)
+	err = w.SetZoomLevel(context.Background(), 1)
+	assert.NoError(t, err)
+	err = w.SetVisualZoomLevelLimits(context.Background(), 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"{\"name\":\"" + EventNameWindowCmdSetZoomFactor + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"1\",\"zoomFactor\":1.5}\n",
+		"{\"name\":\"" + EventNameWindowCmdSetZoomLevel + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"2\",\"zoomLevel\":1}\n",
+		"{\"name\":\"" + EventNameWindowCmdSetVisualZoomLimits + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"3\",\"zoomLevelLimits\":{\"maximum\":3,\"minimum\":1}}\n",
+	}, wrt.w)
+
+	// Get
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "4", Name: EventNameWindowEventGetZoomFactor, TargetID: w.id, ZoomFactor: PtrFloat(2)})
+	}
+	f, err := w.GetZoomFactor(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, f)
+}
+
+func TestWindow_FindInPage(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Find
+	var wg sync.WaitGroup
+	var r FoundInPageResult
+	w.OnFoundInPage(func(i FoundInPageResult) {
+		r = i
+		wg.Done()
+	})
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", ID: PtrInt(2), Name: EventNameWindowEventFindInPage, TargetID: w.id})
+	}
+	id, err := w.FindInPage(context.Background(), "text", FindInPageOptions{MatchCase: PtrBool(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdFindInPage + "\",\"targetID\":\"" + w.id + "\",\"callbackId\":\"1\",\"findInPageOptions\":{\"matchCase\":true},\"text\":\"text\"}\n"}, wrt.w)
+	wg.Add(1)
+	a.dispatcher.dispatch(Event{FoundInPage: &FoundInPageResult{ActiveMatchOrdinal: 1, FinalUpdate: true, Matches: 3, RequestID: 2}, Name: EventNameWindowEventFoundInPage, TargetID: w.id})
+	wg.Wait()
+	assert.Equal(t, FoundInPageResult{ActiveMatchOrdinal: 1, FinalUpdate: true, Matches: 3, RequestID: 2}, r)
+
+	// Stop
+	wrt.w = []string{}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNameWindowEventStopFindInPage, TargetID: w.id})
+	}
+	err = w.StopFindInPage(context.Background(), StopFindInPageActionClearSelection)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdStopFindInPage + "\",\"targetID\":\"" + w.id + "\",\"action\":\"clearSelection\",\"callbackId\":\"2\"}\n"}, wrt.w)
+}
+
+func TestWindow_Setters(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Actions
+	var names []string
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: strconv.Itoa(len(wrt.w)), Name: names[len(wrt.w)-1], TargetID: w.id})
+	}
+	for _, c := range []struct {
+		eventNameCmd  string
+		eventNameDone string
+		fn            func(ctx context.Context) error
+		options       string
+	}{
+		{eventNameCmd: EventNameWindowCmdSetAlwaysOnTop, eventNameDone: EventNameWindowEventSetAlwaysOnTop, fn: func(ctx context.Context) error { return w.SetAlwaysOnTop(ctx, true) }, options: "{\"alwaysOnTop\":true}"},
+		{eventNameCmd: EventNameWindowCmdSetBackgroundColor, eventNameDone: EventNameWindowEventSetBackgroundColor, fn: func(ctx context.Context) error { return w.SetBackgroundColor(ctx, "#FFF") }, options: "{\"backgroundColor\":\"#FFF\"}"},
+		{eventNameCmd: EventNameWindowCmdSetClosable, eventNameDone: EventNameWindowEventSetClosable, fn: func(ctx context.Context) error { return w.SetClosable(ctx, false) }, options: "{\"closable\":false}"},
+		{eventNameCmd: EventNameWindowCmdSetFullScreen, eventNameDone: EventNameWindowEventSetFullScreen, fn: func(ctx context.Context) error { return w.SetFullScreen(ctx, true) }, options: "{\"fullscreen\":true}"},
+		{eventNameCmd: EventNameWindowCmdSetIcon, eventNameDone: EventNameWindowEventSetIcon, fn: func(ctx context.Context) error { return w.SetIcon(ctx, "/path/to/icon") }, options: "{\"icon\":\"/path/to/icon\"}"},
+		{eventNameCmd: EventNameWindowCmdSetKiosk, eventNameDone: EventNameWindowEventSetKiosk, fn: func(ctx context.Context) error { return w.SetKiosk(ctx, true) }, options: "{\"kiosk\":true}"},
+		{eventNameCmd: EventNameWindowCmdSetMaximizable, eventNameDone: EventNameWindowEventSetMaximizable, fn: func(ctx context.Context) error { return w.SetMaximizable(ctx, false) }, options: "{\"maximizable\":false}"},
+		{eventNameCmd: EventNameWindowCmdSetMaximumSize, eventNameDone: EventNameWindowEventSetMaximumSize, fn: func(ctx context.Context) error { return w.SetMaximumSize(ctx, 3, 4) }, options: "{\"maxHeight\":4,\"maxWidth\":3}"},
+		{eventNameCmd: EventNameWindowCmdSetMinimizable, eventNameDone: EventNameWindowEventSetMinimizable, fn: func(ctx context.Context) error { return w.SetMinimizable(ctx, false) }, options: "{\"minimizable\":false}"},
+		{eventNameCmd: EventNameWindowCmdSetMinimumSize, eventNameDone: EventNameWindowEventSetMinimumSize, fn: func(ctx context.Context) error { return w.SetMinimumSize(ctx, 1, 2) }, options: "{\"minHeight\":2,\"minWidth\":1}"},
+		{eventNameCmd: EventNameWindowCmdSetMovable, eventNameDone: EventNameWindowEventSetMovable, fn: func(ctx context.Context) error { return w.SetMovable(ctx, false) }, options: "{\"movable\":false}"},
+		{eventNameCmd: EventNameWindowCmdSetOpacity, eventNameDone: EventNameWindowEventSetOpacity, fn: func(ctx context.Context) error { return w.SetOpacity(ctx, 0.5) }, options: "{\"opacity\":0.5}"},
+		{eventNameCmd: EventNameWindowCmdSetResizable, eventNameDone: EventNameWindowEventSetResizable, fn: func(ctx context.Context) error { return w.SetResizable(ctx, false) }, options: "{\"resizable\":false}"},
+		{eventNameCmd: EventNameWindowCmdSetSkipTaskbar, eventNameDone: EventNameWindowEventSetSkipTaskbar, fn: func(ctx context.Context) error { return w.SetSkipTaskbar(ctx, true) }, options: "{\"skipTaskbar\":true}"},
+	} {
+		names = append(names, c.eventNameDone)
+		err = c.fn(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"name\":\""+c.eventNameCmd+"\",\"targetID\":\""+w.id+"\",\"callbackId\":\""+strconv.Itoa(len(names))+"\",\"windowOptions\":"+c.options+"}\n", wrt.w[len(wrt.w)-1])
+	}
+	names = append(names, EventNameWindowEventSetProgressBar)
+	err = w.SetProgressBar(context.Background(), 0.5, ProgressBarModePaused)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\""+EventNameWindowCmdSetProgressBar+"\",\"targetID\":\""+w.id+"\",\"callbackId\":\""+strconv.Itoa(len(names))+"\",\"progressBar\":{\"mode\":\"paused\",\"progress\":0.5}}\n", wrt.w[len(wrt.w)-1])
+
+	// Options
+	assert.Equal(t, true, *w.o.AlwaysOnTop)
+	assert.Equal(t, "#FFF", *w.o.BackgroundColor)
+	assert.Equal(t, true, *w.o.Fullscreen)
+	assert.Equal(t, 4, *w.o.MaxHeight)
+	assert.Equal(t, 1, *w.o.MinWidth)
+	assert.Equal(t, 0.5, *w.o.Opacity)
+	assert.Equal(t, false, *w.o.Resizable)
+
+	// Options are left untouched when Electron doesn't apply them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, w.SetOpacity(ctx, 0.2))
+	assert.Equal(t, 0.5, *w.o.Opacity)
+}
+
+func TestWindow_State(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{AlwaysOnTop: PtrBool(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, WindowState{IsAlwaysOnTop: true, URL: "http://test.com"}, w.CachedState())
+
+	// Setter
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameWindowEventSetFullScreen, TargetID: w.id})
+	}
+	err = w.SetFullScreen(context.Background(), true)
+	assert.NoError(t, err)
+	var s = w.CachedState()
+	assert.True(t, s.IsFullScreen)
+
+	// Query
+	var e = WindowState{Bounds: &RectangleOptions{SizeOptions: SizeOptions{Height: PtrInt(1), Width: PtrInt(2)}}, IsFocused: true, Title: "title", URL: "http://test.com/page"}
+	wrt.fn = func() {
+		a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNameWindowEventGetState, TargetID: w.id, WindowState: &e})
+	}
+	s, err = w.State(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, e, s)
+	assert.Equal(t, e, w.CachedState())
+}
+
+func TestWindow_Bounds(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+
+	// Debounced listeners
+	var m sync.Mutex
+	var count int
+	var moved, resized = make(chan RectangleOptions, 2), make(chan RectangleOptions, 2)
+	w.OnMoved(time.Millisecond, func(b RectangleOptions) {
+		m.Lock()
+		count++
+		m.Unlock()
+		moved <- b
+	})
+	w.OnResized(time.Millisecond, func(b RectangleOptions) { resized <- b })
+	a.dispatcher.dispatch(Event{Bounds: &RectangleOptions{PositionOptions: PositionOptions{X: PtrInt(1), Y: PtrInt(2)}, SizeOptions: SizeOptions{Height: PtrInt(5), Width: PtrInt(6)}}, Name: EventNameWindowEventMove, TargetID: w.id})
+	a.dispatcher.dispatch(Event{Bounds: &RectangleOptions{PositionOptions: PositionOptions{X: PtrInt(1), Y: PtrInt(2)}, SizeOptions: SizeOptions{Height: PtrInt(5), Width: PtrInt(6)}}, Name: EventNameWindowEventResize, TargetID: w.id})
+	assert.Equal(t, 1, *(<-moved).X)
+	assert.Equal(t, 5, *(<-resized).Height)
+
+	// Options are updated by another listener of the same events which may run after the debounced ones
+	var updated bool
+	for i := 0; i < 100 && !updated; i++ {
+		w.m.Lock()
+		updated = w.o.X != nil && w.o.Width != nil
+		w.m.Unlock()
+		if !updated {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	w.m.Lock()
+	assert.Equal(t, 1, *w.o.X)
+	assert.Equal(t, 2, *w.o.Y)
+	assert.Equal(t, 5, *w.o.Height)
+	assert.Equal(t, 6, *w.o.Width)
+	assert.Equal(t, 6, *w.state.Bounds.Width)
+	w.m.Unlock()
+
+	// Debounced listeners are executed once
+	time.Sleep(10 * time.Millisecond)
+	m.Lock()
+	assert.Equal(t, 1, count)
+	m.Unlock()
+}
+
+func TestWindow_Parent(t *testing.T) {
+	a, err := New(Options{})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{}
+	a.writer = newWriter(wrt)
+	p, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+	c, err := a.NewChildWindow(p, "http://test.com", &WindowOptions{Modal: PtrBool(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, p, c.Parent())
+	assert.Equal(t, []*Window{c}, p.Children())
+	testObjectAction(t, func() error { return c.Create() }, c.object, wrt, "{\"name\":\""+EventNameWindowCmdCreate+"\",\"targetID\":\""+c.id+"\",\"parentId\":\""+p.id+"\",\"sessionId\":\""+c.Session.id+"\",\"url\":\"http://test.com\",\"windowOptions\":{\"modal\":true}}\n", EventNameWindowEventDidFinishLoad)
+
+	// Set parent
+	testObjectAction(t, func() error { return c.SetParent(nil) }, c.object, wrt, "{\"name\":\""+EventNameWindowCmdSetParent+"\",\"targetID\":\""+c.id+"\"}\n", EventNameWindowEventSetParent)
+	assert.Nil(t, c.Parent())
+	assert.Len(t, p.Children(), 0)
+	testObjectAction(t, func() error { return c.SetParent(p) }, c.object, wrt, "{\"name\":\""+EventNameWindowCmdSetParent+"\",\"targetID\":\""+c.id+"\",\"parentId\":\""+p.id+"\"}\n", EventNameWindowEventSetParent)
+	assert.Equal(t, p, c.Parent())
+
+	// Show modal
+	wrt.w = []string{}
+	wrt.fn = func() {
+		if len(wrt.w) == 1 {
+			a.dispatcher.dispatch(Event{Name: EventNameWindowEventShow, TargetID: c.id})
+			a.dispatcher.dispatch(Event{Message: newEventMessage([]byte("\"ok\"")), Name: EventNameWindowEventModalResult, TargetID: c.id})
+		}
+	}
+	m, err := c.ShowModal(context.Background())
+	assert.NoError(t, err)
+	var s string
+	assert.NoError(t, m.Unmarshal(&s))
+	assert.Equal(t, "ok", s)
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdShow + "\",\"targetID\":\"" + c.id + "\"}\n", "{\"name\":\"" + EventNameWindowCmdClose + "\",\"targetID\":\"" + c.id + "\"}\n"}, wrt.w)
+
+	// Show modal with context done
+	wrt.w = []string{}
+	ctx, cancel := context.WithCancel(context.Background())
+	wrt.fn = func() {
+		if len(wrt.w) == 1 {
+			a.dispatcher.dispatch(Event{Name: EventNameWindowEventShow, TargetID: c.id})
+			cancel()
+		}
+	}
+	m, err = c.ShowModal(ctx)
+	assert.EqualError(t, err, context.Canceled.Error())
+	assert.Nil(t, m)
+	assert.Len(t, wrt.w, 2)
+	c.m.Lock()
+	assert.Nil(t, c.modalResult)
+	c.m.Unlock()
+
+	// Children are marked as closed along with their parent
+	wrt.fn = nil
+	a.dispatcher.dispatch(Event{Name: EventNameWindowEventClosed, TargetID: p.id})
+	<-c.ctx.Done()
+	assert.Nil(t, c.Parent())
+	assert.Len(t, p.Children(), 0)
+}
+
+func TestWindow_OnBeforeClose(t *testing.T) {
+	a, err := New(Options{BeforeCloseTimeout: 10 * time.Millisecond})
+	assert.NoError(t, err)
+	defer a.Close()
+	wrt := &mockedWriter{wg: &sync.WaitGroup{}}
+	a.writer = newWriter(wrt)
+	w, err := a.NewWindow("http://test.com", &WindowOptions{})
+	assert.NoError(t, err)
+	var block bool
+	var fn = func(ctx context.Context) bool {
+		if block {
+			<-time.After(time.Second)
+		}
+		return false
+	}
+
+	// Handler set before Create is sent along the create event
+	assert.NoError(t, w.OnBeforeClose(fn))
+	assert.Len(t, wrt.w, 0)
+	wrt.wg.Add(1)
+	wrt.fn = func() { a.dispatcher.dispatch(Event{Name: EventNameWindowEventDidFinishLoad, TargetID: w.id}) }
+	assert.NoError(t, w.Create())
+	assert.Contains(t, wrt.w[0], "\"timeout\":10")
+
+	// Handler set after Create intercepts the close right away, a nil handler stops intercepting
+	wrt.fn = nil
+	wrt.w = []string{}
+	wrt.wg.Add(2)
+	assert.NoError(t, w.OnBeforeClose(nil))
+	assert.NoError(t, w.OnBeforeClose(fn))
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdInterceptClose + "\",\"targetID\":\"" + w.id + "\"}\n", "{\"name\":\"" + EventNameWindowCmdInterceptClose + "\",\"targetID\":\"" + w.id + "\",\"timeout\":10}\n"}, wrt.w)
+
+	// Veto
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{CallbackID: "1", Name: EventNameWindowEventClose, TargetID: w.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdCloseCallback + "\",\"targetID\":\"" + w.id + "\",\"allow\":false,\"callbackId\":\"1\"}\n"}, wrt.w)
+
+	// Close returns once the close is vetoed
+	wrt.w = []string{}
+	wrt.wg.Add(2)
+	wrt.fn = func() {
+		if len(wrt.w) == 1 {
+			a.dispatcher.dispatch(Event{CallbackID: "3", Name: EventNameWindowEventClose, TargetID: w.id})
+		}
+	}
+	assert.Equal(t, ErrWindowCloseVetoed, w.Close())
+	wrt.wg.Wait()
+	wrt.fn = nil
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdClose + "\",\"targetID\":\"" + w.id + "\"}\n", "{\"name\":\"" + EventNameWindowCmdCloseCallback + "\",\"targetID\":\"" + w.id + "\",\"allow\":false,\"callbackId\":\"3\"}\n"}, wrt.w)
+
+	// Timeout
+	block = true
+	wrt.w = []string{}
+	wrt.wg.Add(1)
+	a.dispatcher.dispatch(Event{CallbackID: "2", Name: EventNameWindowEventClose, TargetID: w.id})
+	wrt.wg.Wait()
+	assert.Equal(t, []string{"{\"name\":\"" + EventNameWindowCmdCloseCallback + "\",\"targetID\":\"" + w.id + "\",\"allow\":true,\"callbackId\":\"2\"}\n"}, wrt.w)
+}